@@ -0,0 +1,217 @@
+package EFEMTest
+
+import (
+	. "ProtocolNexus/backend"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 얼라이너 명령 코드 (로드포트와 같은 ASCII 방식)
+const (
+	algCmdAlign  = "ALGN" // MOV: 정렬, 인자 == 목표 노치 각도, 완료 시 "INF:ALGN/노치각,X오프셋,Y오프셋;"
+	algCmdInit   = "INIT" // MOV: 오류 해제 후 원점 복귀
+	algCmdVacOn  = "VACN" // SET: 진공 흡착
+	algCmdVacOff = "VACF" // SET: 진공 해제
+)
+
+const (
+	algMoveTimeout  = 30 * time.Second
+	algStatusLength = 8
+)
+
+// AlignerStatus는 GET:STAS 응답(8자리)과 마지막 정렬 결과
+// 상태 자리: [0] 장비 "0"/"A"/"E", [1] 원점 완료, [2] 동작 중, [3] 웨이퍼 있음, [4] 진공 ON, [5:7] 오류 코드, [7] 정렬 "0" 안 함/"1" 완료/"2" 실패
+type AlignerStatus struct {
+	Name      string  `json:"name"`
+	Connected bool    `json:"connected"`
+	Run       bool    `json:"run"`
+	Stop      bool    `json:"stop"`
+	Origin    bool    `json:"origin"`
+	Overrun   bool    `json:"overrun"` // 정렬 실패 (노치 검출 실패 등)
+	Alarm     bool    `json:"alarm"`
+	ErrorCode string  `json:"errorCode"`
+	Wafer     bool    `json:"wafer"`
+	Vacuum    bool    `json:"vacuum"`
+	Aligned   bool    `json:"aligned"`
+	Notch     float64 `json:"notch"`   // 마지막 정렬 노치 각도 (deg)
+	OffsetX   float64 `json:"offsetX"` // 마지막 정렬 중심 오프셋 (mm)
+	OffsetY   float64 `json:"offsetY"`
+	Raw       string  `json:"raw"`
+}
+
+// AlignResult는 Align 완료 응답을 해석한 결과
+type AlignResult struct {
+	Notch   float64 `json:"notch"`
+	OffsetX float64 `json:"offsetX"`
+	OffsetY float64 `json:"offsetY"`
+}
+
+// AlignerEventHandler는 상태가 바뀔 때마다 호출
+type AlignerEventHandler func(status AlignerStatus)
+
+func parseAlignerStatus(name, raw string) (AlignerStatus, error) {
+	if len(raw) < algStatusLength {
+		return AlignerStatus{}, fmt.Errorf("%s 상태 길이가 올바르지 않습니다: %q", name, raw)
+	}
+	return AlignerStatus{
+		Name:      name,
+		Connected: true,
+		Run:       raw[2] == '1',
+		Stop:      raw[2] == '0',
+		Origin:    raw[1] == '1',
+		Overrun:   raw[7] == '2',
+		Alarm:     raw[0] != '0',
+		ErrorCode: raw[5:7],
+		Wafer:     raw[3] == '1',
+		Vacuum:    raw[4] == '1',
+		Aligned:   raw[7] == '1',
+		Raw:       raw,
+	}, nil
+}
+
+// parseAlignResult는 "노치각,X오프셋,Y오프셋"을 해석
+func parseAlignResult(detail string) (AlignResult, error) {
+	fields := strings.Split(detail, ",")
+	if len(fields) != 3 {
+		return AlignResult{}, fmt.Errorf("정렬 결과 형식이 올바르지 않습니다: %q", detail)
+	}
+	var values [3]float64
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return AlignResult{}, fmt.Errorf("정렬 결과 형식이 올바르지 않습니다: %q", detail)
+		}
+		values[i] = v
+	}
+	return AlignResult{Notch: values[0], OffsetX: values[1], OffsetY: values[2]}, nil
+}
+
+// Aligner는 장비 세션 위에서 동작하는 얼라이너 드라이버
+type Aligner struct {
+	*asciiDevice
+	onStatus AlignerEventHandler
+	status   AlignerStatus
+	result   AlignResult
+	statusMu sync.Mutex
+}
+
+var (
+	aligners   = make(map[string]*Aligner)
+	alignersMu sync.Mutex
+)
+
+// ConnectAligner는 얼라이너(ALG1, ALG2) 세션을 열고 상태 폴링을 시작
+func ConnectAligner(name string, cfg SessionConfig, onEvent EventHandler, onStatus AlignerEventHandler) (*Aligner, error) {
+	DisconnectAligner(name)
+
+	device, err := connectASCIIDevice(name, cfg, onEvent)
+	if err != nil {
+		return nil, err
+	}
+	alg := &Aligner{asciiDevice: device, onStatus: onStatus}
+
+	alignersMu.Lock()
+	aligners[name] = alg
+	alignersMu.Unlock()
+
+	go alg.poll(func() error {
+		_, err := alg.ReadStatus()
+		return err
+	})
+	return alg, nil
+}
+
+// DisconnectAligner는 폴링을 멈추고 세션을 닫음
+func DisconnectAligner(name string) error {
+	alignersMu.Lock()
+	alg, ok := aligners[name]
+	delete(aligners, name)
+	alignersMu.Unlock()
+	if ok {
+		return alg.close()
+	}
+	return DisconnectDevice(name)
+}
+
+// AlignerByName은 연결된 얼라이너 드라이버를 찾음
+func AlignerByName(name string) (*Aligner, error) {
+	alignersMu.Lock()
+	defer alignersMu.Unlock()
+	alg, ok := aligners[name]
+	if !ok {
+		return nil, fmt.Errorf("%s 얼라이너가 연결되어 있지 않습니다", name)
+	}
+	return alg, nil
+}
+
+// Align은 노치를 angle(deg)로 맞추고 노치 각도와 중심 오프셋을 반환
+func (alg *Aligner) Align(angle float64) (AlignResult, error) {
+	detail, err := alg.command("MOV", algCmdAlign, strconv.FormatFloat(angle, 'f', -1, 64), true, algMoveTimeout)
+	if err != nil {
+		return AlignResult{}, err
+	}
+	result, err := parseAlignResult(detail)
+	if err != nil {
+		return result, fmt.Errorf("%s %v", alg.Name, err)
+	}
+
+	alg.statusMu.Lock()
+	alg.result = result
+	status := alg.status
+	alg.statusMu.Unlock()
+	alg.update(status) // 정렬 결과를 바로 알림
+	return result, nil
+}
+
+// Reset은 오류를 해제하고 원점 복귀
+func (alg *Aligner) Reset() error {
+	_, err := alg.command("MOV", algCmdInit, "", true, algMoveTimeout)
+	return err
+}
+
+func (alg *Aligner) VacuumOn() error {
+	_, err := alg.command("SET", algCmdVacOn, "", false, 0)
+	return err
+}
+
+func (alg *Aligner) VacuumOff() error {
+	_, err := alg.command("SET", algCmdVacOff, "", false, 0)
+	return err
+}
+
+// Status는 마지막으로 읽은 상태
+func (alg *Aligner) Status() AlignerStatus {
+	alg.statusMu.Lock()
+	defer alg.statusMu.Unlock()
+	return alg.status
+}
+
+// ReadStatus는 상태를 즉시 읽고 바뀌었으면 알림
+func (alg *Aligner) ReadStatus() (AlignerStatus, error) {
+	raw, err := alg.command("GET", asciiStatusCode, "", false, 0)
+	if err != nil {
+		alg.update(AlignerStatus{Name: alg.Name})
+		return AlignerStatus{Name: alg.Name}, err
+	}
+	status, err := parseAlignerStatus(alg.Name, raw)
+	if err != nil {
+		return status, err
+	}
+	alg.update(status)
+	return alg.Status(), nil
+}
+
+// update는 마지막 정렬 결과를 붙여 저장하고 바뀌었으면 알림
+func (alg *Aligner) update(status AlignerStatus) {
+	alg.statusMu.Lock()
+	status.Notch, status.OffsetX, status.OffsetY = alg.result.Notch, alg.result.OffsetX, alg.result.OffsetY
+	changed := alg.status != status
+	alg.status = status
+	alg.statusMu.Unlock()
+	if changed && alg.onStatus != nil {
+		alg.onStatus(status)
+	}
+}
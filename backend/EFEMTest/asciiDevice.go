@@ -0,0 +1,243 @@
+package EFEMTest
+
+import (
+	. "ProtocolNexus/backend"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TAS300 방식 ASCII 명령 ("MOV:CLOD;" 전송 -> "ACK:CLOD;" 수락 -> "INF:CLOD;" 완료)
+// 거부는 "NAK:CODE/원인;", 동작 실패는 "ABS:CODE/원인;", 인자와 결과는 "/" 뒤에 붙음
+const (
+	asciiStatusCode    = "STAS"
+	asciiStatusCommand = "GET:" + asciiStatusCode + ";"
+	asciiAckTimeout    = 2 * time.Second
+	asciiPollInterval  = time.Second
+)
+
+type asciiReply struct {
+	kind   string // ACK, NAK, INF, ABS
+	detail string
+}
+
+// asciiDevice는 로드포트, 얼라이너 등 ASCII 명령 장비 드라이버의 공통 부분
+// 응답을 명령 코드로 짝짓고 GET:STAS 상태 폴링을 돌림
+type asciiDevice struct {
+	Name      string
+	sessionID string
+	unlisten  func()
+	stopPoll  chan struct{}
+	closed    <-chan struct{} // 세션이 닫히면 (페이지 전환, 연결 끊김) 폴링도 멈춤
+
+	// 응답 대기 (코드 -> 채널), mu로 보호
+	replies     map[string]chan asciiReply
+	completions map[string]chan asciiReply
+	mu          sync.Mutex
+	cmdMu       sync.Mutex // 전송부터 ACK까지 한 번에 하나
+}
+
+// connectASCIIDevice는 장비 세션을 열고 응답 수신을 시작 (종료 문자, 프레이밍 기본값 CR)
+// 상태 폴링 송수신은 onEvent로 전달하지 않음 (로그가 넘치지 않도록)
+func connectASCIIDevice(name string, cfg SessionConfig, onEvent EventHandler) (*asciiDevice, error) {
+	if cfg.TxTerminator == "" {
+		cfg.TxTerminator = "CR"
+	}
+	if cfg.Framing == (FramingConfig{}) {
+		cfg.Framing = FramingConfig{Mode: "delimiter", Delimiter: "CR", FlushTimeoutMs: 500}
+	}
+	filtered := func(sessionID, dataType, data string) {
+		if data == asciiStatusCommand || strings.HasPrefix(data, "ACK:"+asciiStatusCode) {
+			return
+		}
+		if onEvent != nil {
+			onEvent(sessionID, dataType, data)
+		}
+	}
+
+	sessionID, err := ConnectDevice(name, cfg, filtered)
+	if err != nil {
+		return nil, err
+	}
+	d := &asciiDevice{Name: name, sessionID: sessionID, stopPoll: make(chan struct{}),
+		replies: make(map[string]chan asciiReply), completions: make(map[string]chan asciiReply)}
+	if d.closed, err = SessionClosed(sessionID); err != nil {
+		DisconnectDevice(name)
+		return nil, err
+	}
+	if d.unlisten, err = ListenSession(sessionID, d.handleMessage); err != nil {
+		DisconnectDevice(name)
+		return nil, err
+	}
+	return d, nil
+}
+
+// asciiDeviceByName은 연결된 로드포트, 얼라이너, 로봇 드라이버 중 이름이 같은 장비
+func asciiDeviceByName(name string) (*asciiDevice, error) {
+	if lp, err := LoadportByName(name); err == nil {
+		return lp.asciiDevice, nil
+	}
+	if alg, err := AlignerByName(name); err == nil {
+		return alg.asciiDevice, nil
+	}
+	if robot, err := WTRByName(name); err == nil {
+		return robot.asciiDevice, nil
+	}
+	return nil, fmt.Errorf("%s 는 장비 드라이버로 연결되어 있지 않습니다", name)
+}
+
+// close는 폴링과 수신을 멈추고 세션을 닫음
+func (d *asciiDevice) close() error {
+	close(d.stopPoll)
+	d.unlisten()
+	return DisconnectDevice(d.Name)
+}
+
+// poll은 read를 주기적으로 호출 (close 또는 세션 종료까지)
+func (d *asciiDevice) poll(read func() error) {
+	ticker := time.NewTicker(asciiPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := read(); err != nil {
+			fmt.Printf("[%s] 상태 읽기 실패: %v\n", d.Name, err)
+		}
+		select {
+		case <-d.stopPoll:
+			return
+		case <-d.closed:
+			fmt.Printf("[%s] 세션이 닫혀 상태 읽기를 멈춥니다.\n", d.Name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// command는 "PREFIX:CODE[/ARG];"를 보내고 ACK를 확인, complete면 INF(완료)까지 최대 timeout 기다림
+// ACK(또는 INF)의 "/" 뒤 내용을 반환
+func (d *asciiDevice) command(prefix, code, arg string, complete bool, timeout time.Duration) (string, error) {
+	return d.commandContext(context.Background(), prefix, code, arg, complete, timeout)
+}
+
+// rawCommand는 사이클 단계의 "PREFIX:CODE[/ARG];" 원문을 command로 보냄 (MOV는 INF 완료까지)
+// 응답을 명령 코드로 짝짓기 때문에 상태 폴링 응답과 섞이지 않음, 최종 응답 원문("INF:CODE/DETAIL;")을 반환
+func (d *asciiDevice) rawCommand(ctx context.Context, text string, timeout time.Duration) (string, error) {
+	prefix, rest, ok := strings.Cut(strings.TrimSuffix(strings.TrimSpace(text), ";"), ":")
+	code, arg, _ := strings.Cut(rest, "/")
+	if !ok || prefix == "" || code == "" {
+		return "", fmt.Errorf("%s 명령 형식이 아닙니다 (PREFIX:CODE[/ARG];): %s", d.Name, text)
+	}
+	complete := prefix == "MOV"
+	detail, err := d.commandContext(ctx, prefix, code, arg, complete, timeout)
+	if err != nil {
+		return "", err
+	}
+	reply := "ACK:" + code
+	if complete {
+		reply = "INF:" + code
+	}
+	if detail != "" {
+		reply += "/" + detail
+	}
+	return reply + ";", nil
+}
+
+// commandContext는 ctx가 취소되면 (EMO 등) 응답을 기다리지 않고 바로 반환
+func (d *asciiDevice) commandContext(ctx context.Context, prefix, code, arg string, complete bool, timeout time.Duration) (string, error) {
+	message := prefix + ":" + code
+	if arg != "" {
+		message += "/" + arg
+	}
+
+	d.cmdMu.Lock()
+	reply := d.await(d.replies, code)
+	var done chan asciiReply
+	if complete {
+		done = d.await(d.completions, code)
+	}
+	defer d.cancel(d.completions, code, done)
+
+	err := SessionSend(d.sessionID, message+";")
+	var ack asciiReply
+	if err == nil {
+		ack, err = d.wait(ctx, reply, asciiAckTimeout, code)
+	}
+	d.cancel(d.replies, code, reply)
+	d.cmdMu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	if ack.kind == "NAK" {
+		return "", fmt.Errorf("%s %s 명령 거부: %s", d.Name, code, ack.detail)
+	}
+	if !complete {
+		return ack.detail, nil
+	}
+
+	inf, err := d.wait(ctx, done, timeout, code)
+	if err != nil {
+		return "", err
+	}
+	if inf.kind == "ABS" {
+		return "", fmt.Errorf("%s %s 동작 실패: %s", d.Name, code, inf.detail)
+	}
+	return inf.detail, nil
+}
+
+func (d *asciiDevice) await(waiters map[string]chan asciiReply, code string) chan asciiReply {
+	ch := make(chan asciiReply, 1)
+	d.mu.Lock()
+	waiters[code] = ch
+	d.mu.Unlock()
+	return ch
+}
+
+func (d *asciiDevice) cancel(waiters map[string]chan asciiReply, code string, ch chan asciiReply) {
+	if ch == nil {
+		return
+	}
+	d.mu.Lock()
+	if waiters[code] == ch {
+		delete(waiters, code)
+	}
+	d.mu.Unlock()
+}
+
+func (d *asciiDevice) wait(ctx context.Context, ch chan asciiReply, timeout time.Duration, code string) (asciiReply, error) {
+	select {
+	case r := <-ch:
+		return r, nil
+	case <-ctx.Done():
+		return asciiReply{}, ctx.Err()
+	case <-time.After(timeout):
+		return asciiReply{}, fmt.Errorf("%s %s 응답 대기 시간 초과 (%v)", d.Name, code, timeout)
+	}
+}
+
+// handleMessage는 "KIND:CODE/DETAIL;" 응답을 기다리는 명령에 전달
+func (d *asciiDevice) handleMessage(message string) {
+	kind, rest, ok := strings.Cut(strings.TrimSuffix(strings.TrimSpace(message), ";"), ":")
+	if !ok {
+		return
+	}
+	code, detail, _ := strings.Cut(rest, "/")
+
+	var waiters map[string]chan asciiReply
+	switch kind {
+	case "ACK", "NAK":
+		waiters = d.replies
+	case "INF", "ABS":
+		waiters = d.completions
+	default:
+		return
+	}
+
+	d.mu.Lock()
+	ch, ok := waiters[code]
+	delete(waiters, code)
+	d.mu.Unlock()
+	if ok {
+		ch <- asciiReply{kind: kind, detail: detail}
+	}
+}
@@ -0,0 +1,295 @@
+package EFEMTest
+
+import (
+	. "ProtocolNexus/backend"
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// CycleStep은 시퀀스의 한 단계, Device 세션으로 Command를 보내고 Expect 응답을 기다림
+type CycleStep struct {
+	Name      string `json:"name"`
+	Device    string `json:"device"`    // 장비 이름 (LP1, ALG1, WTR1 ...), 비어 있으면 대기만
+	Command   string `json:"command"`   // 비어 있으면 전송 없이 DelayMs만 대기, 드라이버 장비는 "PREFIX:CODE[/ARG];"
+	Expect    string `json:"expect"`    // 응답 정규식 (드라이버 장비는 ACK 또는 MOV의 INF 원문과 비교, 비어 있으면 성공 응답이면 통과)
+	TimeoutMs int    `json:"timeoutMs"` // 응답 대기 (기본 5초)
+	DelayMs   int    `json:"delayMs"`   // 단계 완료 후 대기
+	// ExpectMap은 Device(로드포트)의 매핑 결과와 비교할 기대 맵 ("1111000...", '?'는 무시), 다르면 실패
+	ExpectMap string `json:"expectMap"`
+}
+
+// CycleSequence는 시스템별 사이클 테스트 시퀀스 (한 사이클 == Steps 한 번)
+type CycleSequence struct {
+	Name       string      `json:"name"`   // 시퀀스 파일 이름 (시스템 설정의 sequences 중 하나)
+	System     string      `json:"system"` // 실행한 시스템 (시작 시 연결된 시스템으로 채움)
+	Steps      []CycleStep `json:"steps"`
+	StopOnFail bool        `json:"stopOnFail"` // true면 실패한 사이클에서 테스트 중단
+	// EmoCommands는 EMO 때 장비별로 보내는 정지 명령 (장비 이름 -> 명령)
+	EmoCommands map[string]string `json:"emoCommands"`
+}
+
+// CycleResult는 사이클 하나의 결과
+type CycleResult struct {
+	Cycle      int    `json:"cycle"`
+	Passed     bool   `json:"passed"`
+	FailedStep string `json:"failedStep"`
+	Message    string `json:"message"`
+	ElapsedMs  int64  `json:"elapsedMs"`
+}
+
+// CycleProgress는 사이클 테스트 진행 이벤트
+// State == {"START", "STEP", "CYCLE", "DONE", "STOPPED", "ABORTED"}
+type CycleProgress struct {
+	State    string       `json:"state"`
+	System   string       `json:"system"`
+	Cycle    int          `json:"cycle"`  // 현재 반복 (1부터)
+	Repeat   int          `json:"repeat"` // 전체 반복 횟수
+	Step     int          `json:"step"`   // 현재 단계 (1부터)
+	StepName string       `json:"stepName"`
+	Message  string       `json:"message"`
+	Result   *CycleResult `json:"result"` // State == "CYCLE"일 때 사이클 결과
+	Passed   int          `json:"passed"`
+	Failed   int          `json:"failed"`
+}
+
+// CycleEventHandler는 진행 이벤트를 상위(App)로 전달
+type CycleEventHandler func(progress CycleProgress)
+
+type cycleRunner struct {
+	seq        CycleSequence
+	repeat     int
+	onProgress CycleEventHandler
+	results    []CycleResult
+
+	stopping bool               // Stop: 진행 중인 단계를 마치고 종료
+	abort    context.CancelFunc // EMO: 즉시 중단
+	ctx      context.Context
+	done     chan struct{}
+}
+
+var (
+	cycleMu     sync.Mutex
+	cycleActive *cycleRunner
+	cycleLast   []CycleResult
+)
+
+// CycleTestStart는 시퀀스를 repeat번 반복 실행 (동시에 하나만)
+func CycleTestStart(seq CycleSequence, repeat int, onProgress CycleEventHandler) error {
+	if len(seq.Steps) == 0 {
+		return fmt.Errorf("%s 시퀀스에 단계가 없습니다", seq.Name)
+	}
+	if repeat <= 0 {
+		return fmt.Errorf("잘못된 반복 횟수입니다: %d", repeat)
+	}
+	system, err := checkActiveSequence(seq.Name)
+	if err != nil {
+		return err
+	}
+	seq.System = system
+	for i, step := range seq.Steps {
+		if (step.Command != "" || step.ExpectMap != "") && step.Device == "" {
+			return fmt.Errorf("%d번째 단계(%s)에 장비가 없습니다", i+1, step.Name)
+		}
+		if err := validExpectedMap(step.ExpectMap); err != nil {
+			return fmt.Errorf("%d번째 단계(%s) %v", i+1, step.Name, err)
+		}
+	}
+
+	cycleMu.Lock()
+	defer cycleMu.Unlock()
+	if cycleActive != nil {
+		return fmt.Errorf("사이클 테스트가 이미 실행 중입니다")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := &cycleRunner{seq: seq, repeat: repeat, onProgress: onProgress, abort: cancel, ctx: ctx, done: make(chan struct{})}
+	cycleActive = runner
+	go runner.run()
+	return nil
+}
+
+// CycleTestStop은 진행 중인 단계가 끝나면 테스트를 종료
+func CycleTestStop() error {
+	cycleMu.Lock()
+	defer cycleMu.Unlock()
+	if cycleActive == nil {
+		return fmt.Errorf("실행 중인 사이클 테스트가 없습니다")
+	}
+	cycleActive.stopping = true
+	return nil
+}
+
+// CycleTestEMO는 테스트를 즉시 중단하고 시퀀스의 EMO 명령을 장비에 전송
+func CycleTestEMO() []error {
+	cycleMu.Lock()
+	runner := cycleActive
+	cycleMu.Unlock()
+
+	var errs []error
+	if runner != nil {
+		runner.abort()
+		for device, command := range runner.seq.EmoCommands {
+			sessionID, err := DeviceSession(device)
+			if err == nil {
+				err = SessionSend(sessionID, command)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s EMO 전송 실패: %v", device, err))
+			}
+		}
+		<-runner.done
+	}
+	return errs
+}
+
+// CycleTestResults는 실행 중이거나 마지막으로 끝난 테스트의 사이클별 결과
+func CycleTestResults() []CycleResult {
+	cycleMu.Lock()
+	defer cycleMu.Unlock()
+	if cycleActive != nil {
+		return append([]CycleResult(nil), cycleActive.results...)
+	}
+	return append([]CycleResult(nil), cycleLast...)
+}
+
+func (r *cycleRunner) emit(progress CycleProgress) {
+	progress.System = r.seq.System
+	progress.Repeat = r.repeat
+	for _, result := range r.results {
+		if result.Passed {
+			progress.Passed++
+		} else {
+			progress.Failed++
+		}
+	}
+	if r.onProgress != nil {
+		r.onProgress(progress)
+	}
+}
+
+func (r *cycleRunner) isStopping() bool {
+	cycleMu.Lock()
+	defer cycleMu.Unlock()
+	return r.stopping
+}
+
+func (r *cycleRunner) run() {
+	state, message := "DONE", "사이클 테스트 완료"
+	r.emit(CycleProgress{State: "START", Message: fmt.Sprintf("%s %s 사이클 테스트 시작 (%d회)", r.seq.System, r.seq.Name, r.repeat)})
+
+	for cycle := 1; cycle <= r.repeat; cycle++ {
+		result, completed := r.runCycle(cycle)
+		if r.ctx.Err() != nil {
+			state, message = "ABORTED", fmt.Sprintf("EMO로 %d번째 사이클에서 중단되었습니다", cycle)
+			break
+		}
+		if !completed {
+			state, message = "STOPPED", fmt.Sprintf("%d번째 사이클 진행 중 정지되었습니다", cycle)
+			break
+		}
+
+		cycleMu.Lock()
+		r.results = append(r.results, result)
+		cycleMu.Unlock()
+		r.emit(CycleProgress{State: "CYCLE", Cycle: cycle, Message: result.Message, Result: &result})
+
+		if !result.Passed && r.seq.StopOnFail {
+			state, message = "STOPPED", fmt.Sprintf("%d번째 사이클 실패로 중단되었습니다", cycle)
+			break
+		}
+		if r.isStopping() {
+			if cycle < r.repeat {
+				state, message = "STOPPED", fmt.Sprintf("%d번째 사이클 후 정지되었습니다", cycle)
+			}
+			break
+		}
+	}
+
+	cycleMu.Lock()
+	cycleActive = nil
+	cycleLast = r.results
+	cycleMu.Unlock()
+	r.emit(CycleProgress{State: state, Message: message})
+	close(r.done)
+}
+
+// runCycle은 단계를 순서대로 실행하고 첫 실패에서 사이클을 끝냄
+// Stop이면 진행 중인 단계를 마친 뒤 남은 단계를 건너뛰고 completed == false (결과에 기록하지 않음)
+func (r *cycleRunner) runCycle(cycle int) (result CycleResult, completed bool) {
+	start := time.Now()
+	result = CycleResult{Cycle: cycle, Passed: true, Message: "PASS"}
+	for index, step := range r.seq.Steps {
+		if index > 0 && r.isStopping() {
+			return result, false
+		}
+		r.emit(CycleProgress{State: "STEP", Cycle: cycle, Step: index + 1, StepName: step.Name, Message: step.Command})
+
+		if err := r.runStep(step); err != nil {
+			result.Passed, result.FailedStep, result.Message = false, step.Name, err.Error()
+			break
+		}
+	}
+	result.ElapsedMs = time.Since(start).Milliseconds()
+	return result, true
+}
+
+func (r *cycleRunner) runStep(step CycleStep) error {
+	if step.Command != "" {
+		if err := r.sendStep(step); err != nil {
+			return err
+		}
+	}
+
+	if step.ExpectMap != "" {
+		lp, err := LoadportByName(step.Device)
+		if err != nil {
+			return err
+		}
+		if _, err := lp.CheckMap(step.ExpectMap); err != nil {
+			return err
+		}
+	}
+
+	if step.DelayMs > 0 {
+		select {
+		case <-time.After(time.Duration(step.DelayMs) * time.Millisecond):
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		}
+	}
+	return nil
+}
+
+// sendStep은 단계 명령을 보내고 응답을 확인 (EMO면 r.ctx 취소로 바로 끝남)
+// 드라이버 장비(LP, ALG, WTR)는 명령 코드로 응답을 짝짓는 드라이버 경로를 써서 상태 폴링 응답과 섞이지 않게 함
+func (r *cycleRunner) sendStep(step CycleStep) error {
+	timeout := time.Duration(step.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	device, err := asciiDeviceByName(step.Device)
+	if err != nil {
+		sessionID, err := DeviceSession(step.Device)
+		if err != nil {
+			return err
+		}
+		_, err = SendAndExpectContext(r.ctx, sessionID, step.Command, step.Expect, timeout)
+		return err
+	}
+
+	reply, err := device.rawCommand(r.ctx, step.Command, timeout)
+	if err != nil || step.Expect == "" {
+		return err
+	}
+	pattern, err := regexp.Compile(step.Expect)
+	if err != nil {
+		return fmt.Errorf("응답 패턴 정규식 오류: %v", err)
+	}
+	if !pattern.MatchString(reply) {
+		return fmt.Errorf("%s 응답이 기대와 다릅니다 (기대 %s, 응답 %s)", step.Device, step.Expect, reply)
+	}
+	return nil
+}
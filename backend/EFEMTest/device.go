@@ -0,0 +1,51 @@
+package EFEMTest
+
+import (
+	. "ProtocolNexus/backend"
+	"fmt"
+	"sync"
+)
+
+// 장비 이름(LP1, ALG1, WTR1 ...)과 세션 ID 연결
+var (
+	devices   = make(map[string]string)
+	devicesMu sync.Mutex
+)
+
+// ConnectDevice는 장비 세션을 열고 이름으로 등록 (이미 연결되어 있으면 닫고 다시 연결)
+func ConnectDevice(name string, cfg SessionConfig, onEvent EventHandler) (string, error) {
+	DisconnectDevice(name)
+
+	sessionID, err := OpenSession(cfg, onEvent)
+	if err != nil {
+		return "", fmt.Errorf("%s 연결 실패: %v", name, err)
+	}
+
+	devicesMu.Lock()
+	devices[name] = sessionID
+	devicesMu.Unlock()
+	return sessionID, nil
+}
+
+// DisconnectDevice는 장비 세션을 닫고 등록을 해제
+func DisconnectDevice(name string) error {
+	devicesMu.Lock()
+	sessionID, ok := devices[name]
+	delete(devices, name)
+	devicesMu.Unlock()
+	if !ok {
+		return nil
+	}
+	return CloseSession(sessionID)
+}
+
+// DeviceSession은 장비 이름으로 세션 ID를 찾음
+func DeviceSession(name string) (string, error) {
+	devicesMu.Lock()
+	defer devicesMu.Unlock()
+	sessionID, ok := devices[name]
+	if !ok {
+		return "", fmt.Errorf("%s 장비가 연결되어 있지 않습니다", name)
+	}
+	return sessionID, nil
+}
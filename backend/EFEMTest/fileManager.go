@@ -2,14 +2,153 @@ package EFEMTest
 
 import (
 	. "ProtocolNexus/backend"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
-var filePath = filepath.Join(ProgramFolderPath, "EFEMTest")
+// ProgramFolderPath는 시작 시 정해지므로 경로는 사용할 때 계산
+func folderPath() string {
+	return filepath.Join(ProgramFolderPath, "EFEMTest")
+}
+
+// SequenceFilePath는 사이클 테스트 시퀀스 파일 경로 (폴더 밖을 가리키는 이름은 거부)
+func SequenceFilePath(name string) (string, error) {
+	if err := validFileName("시퀀스", name); err != nil {
+		return "", err
+	}
+	return filepath.Join(folderPath(), "Sequence", name+".json"), nil
+}
+
+// LoadSequence는 이름의 사이클 테스트 시퀀스를 읽음 (Name은 파일 이름으로 채움)
+func LoadSequence(name string) (CycleSequence, error) {
+	var seq CycleSequence
+	path, err := SequenceFilePath(name)
+	if err != nil {
+		return seq, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return seq, fmt.Errorf("시퀀스 파일 읽기 실패 (%s): %v", path, err)
+	}
+	if err = json.Unmarshal(data, &seq); err != nil {
+		return seq, fmt.Errorf("시퀀스 파일 형식 오류 (%s): %v", path, err)
+	}
+	seq.Name = name
+	return seq, nil
+}
+
+// SaveSequence는 시퀀스를 시퀀스 이름의 파일로 저장
+func SaveSequence(seq CycleSequence) error {
+	path, err := SequenceFilePath(seq.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("시퀀스 폴더 생성 실패: %v", err)
+	}
+	data, err := json.MarshalIndent(seq, "", "  ")
+	if err != nil {
+		return fmt.Errorf("시퀀스 변환 실패: %v", err)
+	}
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("시퀀스 파일 저장 실패 (%s): %v", path, err)
+	}
+	return nil
+}
+
+// SystemFilePath는 EFEM 시스템 설정 파일 경로
+func SystemFilePath(name string) string {
+	return filepath.Join(folderPath(), "System", name+".json")
+}
+
+// ListSystems는 System 폴더의 시스템 이름 목록 (폴더가 비어 있으면 기본 구성을 하나 만듦)
+func ListSystems() ([]string, error) {
+	dir := filepath.Dir(SystemFilePath(""))
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("시스템 폴더 읽기 실패 (%s): %v", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			names = append(names, strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+		}
+	}
+	if len(names) == 0 {
+		cfg, err := CreateSystem("Default 4port")
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, cfg.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadSystem은 시스템 설정을 읽고 확인 (버전이 없는 파일은 현재 버전으로 간주)
+func LoadSystem(name string) (SystemConfig, error) {
+	var cfg SystemConfig
+	if err := validFileName("시스템", name); err != nil {
+		return cfg, err
+	}
+	path := SystemFilePath(name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("시스템 파일 읽기 실패 (%s): %v", path, err)
+	}
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("시스템 파일 형식 오류 (%s): %v", path, err)
+	}
+	if cfg.Version == 0 {
+		cfg.Version = SystemConfigVersion
+	}
+	if cfg.Name == "" {
+		cfg.Name = name
+	}
+	if err = cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// SaveSystem은 확인 후 시스템 이름의 파일로 저장
+func SaveSystem(cfg SystemConfig) error {
+	if cfg.Version == 0 {
+		cfg.Version = SystemConfigVersion
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	path := SystemFilePath(cfg.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("시스템 폴더 생성 실패: %v", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("시스템 설정 변환 실패: %v", err)
+	}
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("시스템 파일 저장 실패 (%s): %v", path, err)
+	}
+	return nil
+}
 
-func f() {
-	if err := os.MkdirAll(filePath, 0755); err != nil {
-		return
+// CreateSystem은 기본 구성으로 새 시스템 파일을 만듦 (이미 있으면 오류)
+func CreateSystem(name string) (SystemConfig, error) {
+	if err := validFileName("시스템", name); err != nil {
+		return SystemConfig{}, err
+	}
+	if _, err := os.Stat(SystemFilePath(name)); err == nil {
+		return SystemConfig{}, fmt.Errorf("이미 있는 시스템입니다: %s", name)
+	}
+	cfg := NewSystemConfig(name)
+	if err := SaveSystem(cfg); err != nil {
+		return cfg, err
 	}
+	return cfg, nil
 }
@@ -0,0 +1,277 @@
+package EFEMTest
+
+import (
+	. "ProtocolNexus/backend"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 로드포트 명령 코드 (GET:STAS는 상태 20자리)
+const (
+	lpCmdLoad   = "CLOD" // MOV: 클램프, 도킹, 도어 열기
+	lpCmdUnload = "CULD" // MOV: 도어 닫기, 언도킹, 언클램프
+	lpCmdOrigin = "ORGN" // MOV: 원점 복귀
+	lpCmdReset  = "RSET" // SET: 오류 해제
+	lpCmdMap    = "MAPR" // GET: 매핑 결과 (슬롯별 한 글자)
+)
+
+const (
+	lpMoveTimeout  = 60 * time.Second
+	lpStatusLength = 20
+)
+
+// LoadportStatus는 GET:STAS 응답을 해석한 상태
+type LoadportStatus struct {
+	Name      string `json:"name"`
+	Connected bool   `json:"connected"`
+	Equipment string `json:"equipment"` // "0" 정상, "A" 복구 가능 오류, "E" 치명 오류
+	Run       bool   `json:"run"`       // 동작 중
+	Stop      bool   `json:"stop"`      // 정지 상태
+	Origin    bool   `json:"origin"`    // 원점 복귀 완료
+	Overrun   bool   `json:"overrun"`   // 웨이퍼 돌출 감지
+	Alarm     bool   `json:"alarm"`     // Equipment != "0"
+	ErrorCode string `json:"errorCode"` // 16진수 2자리, "00"이면 없음
+	Carrier   string `json:"carrier"`   // "none", "present", "error"
+	Clamped   bool   `json:"clamped"`
+	Docked    bool   `json:"docked"`
+	DoorOpen  bool   `json:"doorOpen"`
+	Mapped    bool   `json:"mapped"` // 매핑 정상 완료
+	Raw       string `json:"raw"`
+}
+
+// LoadportEventHandler는 상태가 바뀔 때마다 호출
+type LoadportEventHandler func(status LoadportStatus)
+
+// parseLoadportStatus는 20자리 상태 문자열을 해석
+func parseLoadportStatus(name, raw string) (LoadportStatus, error) {
+	if len(raw) < lpStatusLength {
+		return LoadportStatus{}, fmt.Errorf("%s 상태 길이가 올바르지 않습니다: %q", name, raw)
+	}
+	status := LoadportStatus{
+		Name:      name,
+		Connected: true,
+		Equipment: raw[0:1],
+		Run:       raw[3] == '1',
+		Stop:      raw[3] == '0',
+		Origin:    raw[2] == '1',
+		Overrun:   raw[11] == '0',
+		Alarm:     raw[0] != '0',
+		ErrorCode: raw[4:6],
+		Clamped:   raw[7] == '1',
+		Docked:    raw[13] == '1',
+		DoorOpen:  raw[10] == '0',
+		Mapped:    raw[17] == '1',
+		Raw:       raw,
+	}
+	switch raw[6] {
+	case '0':
+		status.Carrier = "none"
+	case '1':
+		status.Carrier = "present"
+	default:
+		status.Carrier = "error"
+	}
+	return status, nil
+}
+
+// Loadport는 장비 세션 위에서 동작하는 로드포트 드라이버
+type Loadport struct {
+	*asciiDevice
+	onStatus LoadportEventHandler
+	onMap    LoadportMapHandler
+	status   LoadportStatus
+	slotMap  SlotMapResult // 마지막 매핑 결과
+	expected string        // 기대 맵 (비어 있으면 비교 안 함)
+	statusMu sync.Mutex
+}
+
+var (
+	loadports   = make(map[string]*Loadport)
+	loadportsMu sync.Mutex
+)
+
+// ConnectLoadport는 로드포트(LP1~LP4) 세션을 열고 상태 폴링을 시작, 매핑 결과는 onMap으로 전달
+func ConnectLoadport(name string, cfg SessionConfig, onEvent EventHandler, onStatus LoadportEventHandler, onMap LoadportMapHandler) (*Loadport, error) {
+	DisconnectLoadport(name)
+
+	device, err := connectASCIIDevice(name, cfg, onEvent)
+	if err != nil {
+		return nil, err
+	}
+	lp := &Loadport{asciiDevice: device, onStatus: onStatus, onMap: onMap}
+
+	loadportsMu.Lock()
+	loadports[name] = lp
+	loadportsMu.Unlock()
+
+	go lp.poll(func() error {
+		_, err := lp.ReadStatus()
+		return err
+	})
+	return lp, nil
+}
+
+// DisconnectLoadport는 폴링을 멈추고 세션을 닫음
+func DisconnectLoadport(name string) error {
+	loadportsMu.Lock()
+	lp, ok := loadports[name]
+	delete(loadports, name)
+	loadportsMu.Unlock()
+	if ok {
+		return lp.close()
+	}
+	return DisconnectDevice(name)
+}
+
+// LoadportByName은 연결된 로드포트 드라이버를 찾음
+func LoadportByName(name string) (*Loadport, error) {
+	loadportsMu.Lock()
+	defer loadportsMu.Unlock()
+	lp, ok := loadports[name]
+	if !ok {
+		return nil, fmt.Errorf("%s 로드포트가 연결되어 있지 않습니다", name)
+	}
+	return lp, nil
+}
+
+// Load는 FOUP을 클램프, 도킹하고 도어를 연 뒤 매핑 결과를 읽음
+// 기대 맵이 설정되어 있고 다르면 오류
+func (lp *Loadport) Load() error {
+	if _, err := lp.command("MOV", lpCmdLoad, "", true, lpMoveTimeout); err != nil {
+		return err
+	}
+	result, err := lp.ReadMap()
+	if err != nil {
+		return err
+	}
+	return mismatchError(lp.Name, result)
+}
+
+// Unload는 도어를 닫고 언도킹, 언클램프
+func (lp *Loadport) Unload() error {
+	_, err := lp.command("MOV", lpCmdUnload, "", true, lpMoveTimeout)
+	return err
+}
+
+// Origin은 원점 복귀
+func (lp *Loadport) Origin() error {
+	_, err := lp.command("MOV", lpCmdOrigin, "", true, lpMoveTimeout)
+	return err
+}
+
+// ResetError는 복구 가능한 오류를 해제
+func (lp *Loadport) ResetError() error {
+	_, err := lp.command("SET", lpCmdReset, "", false, 0)
+	return err
+}
+
+// SetExpectedMap은 Load/ReadMap 후 비교할 기대 맵을 설정 (빈 문자열이면 비교 안 함)
+func (lp *Loadport) SetExpectedMap(expected string) error {
+	if err := validExpectedMap(expected); err != nil {
+		return fmt.Errorf("%s %v", lp.Name, err)
+	}
+	lp.statusMu.Lock()
+	defer lp.statusMu.Unlock()
+	lp.expected = strings.TrimSpace(expected)
+	return nil
+}
+
+// ReadMap은 매핑 결과를 읽어 기대 맵과 비교하고 onMap으로 알림
+func (lp *Loadport) ReadMap() (SlotMapResult, error) {
+	lp.statusMu.Lock()
+	expected := lp.expected
+	lp.statusMu.Unlock()
+	return lp.readMap(expected)
+}
+
+// CheckMap은 매핑 결과를 읽어 expected와 비교, 다르면 오류
+func (lp *Loadport) CheckMap(expected string) (SlotMapResult, error) {
+	if err := validExpectedMap(expected); err != nil {
+		return SlotMapResult{}, fmt.Errorf("%s %v", lp.Name, err)
+	}
+	result, err := lp.readMap(strings.TrimSpace(expected))
+	if err != nil {
+		return result, err
+	}
+	return result, mismatchError(lp.Name, result)
+}
+
+// SlotMap은 마지막으로 읽은 매핑 결과
+func (lp *Loadport) SlotMap() SlotMapResult {
+	lp.statusMu.Lock()
+	defer lp.statusMu.Unlock()
+	return lp.slotMap
+}
+
+func (lp *Loadport) readMap(expected string) (SlotMapResult, error) {
+	raw, err := lp.command("GET", lpCmdMap, "", false, 0)
+	if err != nil {
+		return SlotMapResult{}, err
+	}
+	slotMap, err := ParseSlotMap(lp.Name, raw)
+	if err != nil {
+		return SlotMapResult{}, err
+	}
+
+	result := SlotMapResult{Map: slotMap, Expected: expected, Matched: true}
+	if expected != "" {
+		if result.Mismatches, err = CompareSlotMap(slotMap, expected); err != nil {
+			return result, fmt.Errorf("%s %v", lp.Name, err)
+		}
+		result.Matched = len(result.Mismatches) == 0
+	}
+
+	lp.statusMu.Lock()
+	lp.slotMap = result
+	lp.statusMu.Unlock()
+	if lp.onMap != nil {
+		lp.onMap(result)
+	}
+	return result, nil
+}
+
+// mismatchError는 기대 맵과 다른 슬롯을 오류로 만듦
+func mismatchError(name string, result SlotMapResult) error {
+	if result.Matched {
+		return nil
+	}
+	var slots []string
+	for _, m := range result.Mismatches {
+		slots = append(slots, fmt.Sprintf("%d번(기대 %s, 실제 %s)", m.Slot, m.Expected, m.Actual))
+	}
+	return fmt.Errorf("%s 매핑 결과 불일치: %s", name, strings.Join(slots, ", "))
+}
+
+// Status는 마지막으로 읽은 상태
+func (lp *Loadport) Status() LoadportStatus {
+	lp.statusMu.Lock()
+	defer lp.statusMu.Unlock()
+	return lp.status
+}
+
+// ReadStatus는 상태를 즉시 읽고 바뀌었으면 알림
+func (lp *Loadport) ReadStatus() (LoadportStatus, error) {
+	raw, err := lp.command("GET", asciiStatusCode, "", false, 0)
+	if err != nil {
+		lp.update(LoadportStatus{Name: lp.Name})
+		return LoadportStatus{Name: lp.Name}, err
+	}
+	status, err := parseLoadportStatus(lp.Name, raw)
+	if err != nil {
+		return status, err
+	}
+	lp.update(status)
+	return status, nil
+}
+
+func (lp *Loadport) update(status LoadportStatus) {
+	lp.statusMu.Lock()
+	changed := lp.status != status
+	lp.status = status
+	lp.statusMu.Unlock()
+	if changed && lp.onStatus != nil {
+		lp.onStatus(status)
+	}
+}
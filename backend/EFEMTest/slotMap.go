@@ -0,0 +1,113 @@
+package EFEMTest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 슬롯 상태 (GET:MAPR 응답은 슬롯 1부터 한 글자씩)
+const (
+	SlotEmpty   = "empty"   // '0'
+	SlotPresent = "present" // '1'
+	SlotCross   = "cross"   // '2' 두 슬롯에 걸쳐 비스듬히 놓임
+	SlotDouble  = "double"  // 'W' 한 슬롯에 두 장
+	SlotUnknown = "unknown" // 그 외 (얇은 웨이퍼, 판독 불가)
+)
+
+// 매핑 결과 슬롯 수 (300mm FOUP 25슬롯, 13슬롯)
+var slotMapSizes = []int{25, 13}
+
+// SlotMap은 매핑 결과 (Slots[0] == 1번 슬롯)
+type SlotMap struct {
+	Name    string   `json:"name"`
+	Slots   []string `json:"slots"`
+	Present int      `json:"present"` // 웨이퍼가 있는 슬롯 수 (present, double, cross)
+	Raw     string   `json:"raw"`
+}
+
+// SlotMismatch는 기대 맵과 다른 슬롯
+type SlotMismatch struct {
+	Slot     int    `json:"slot"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// SlotMapResult는 매핑 결과와 기대 맵 비교 (Expected가 비어 있으면 비교하지 않음)
+type SlotMapResult struct {
+	Map        SlotMap        `json:"map"`
+	Expected   string         `json:"expected"`
+	Matched    bool           `json:"matched"`
+	Mismatches []SlotMismatch `json:"mismatches"`
+}
+
+// LoadportMapHandler는 매핑 결과를 읽을 때마다 호출
+type LoadportMapHandler func(result SlotMapResult)
+
+func slotState(code byte) string {
+	switch code {
+	case '0':
+		return SlotEmpty
+	case '1':
+		return SlotPresent
+	case '2':
+		return SlotCross
+	case 'W', 'w':
+		return SlotDouble
+	}
+	return SlotUnknown
+}
+
+func validSlotMapSize(n int) bool {
+	for _, size := range slotMapSizes {
+		if n == size {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSlotMap은 매핑 결과 문자열("1111000...")을 슬롯별로 해석
+func ParseSlotMap(name, raw string) (SlotMap, error) {
+	raw = strings.TrimSpace(raw)
+	if !validSlotMapSize(len(raw)) {
+		return SlotMap{}, fmt.Errorf("%s 매핑 결과 길이가 올바르지 않습니다: %d (25 또는 13)", name, len(raw))
+	}
+	slotMap := SlotMap{Name: name, Slots: make([]string, len(raw)), Raw: raw}
+	for i := 0; i < len(raw); i++ {
+		slotMap.Slots[i] = slotState(raw[i])
+		if slotMap.Slots[i] != SlotEmpty && slotMap.Slots[i] != SlotUnknown {
+			slotMap.Present++
+		}
+	}
+	return slotMap, nil
+}
+
+// CompareSlotMap은 매핑 결과를 기대 맵과 비교 (기대 맵은 같은 형식, '?'는 어떤 상태든 일치)
+func CompareSlotMap(actual SlotMap, expected string) ([]SlotMismatch, error) {
+	expected = strings.TrimSpace(expected)
+	if len(expected) != len(actual.Slots) {
+		return nil, fmt.Errorf("기대 맵 슬롯 수가 다릅니다: %d (매핑 결과 %d)", len(expected), len(actual.Slots))
+	}
+	var mismatches []SlotMismatch
+	for i := 0; i < len(expected); i++ {
+		if expected[i] == '?' {
+			continue
+		}
+		if want := slotState(expected[i]); want != actual.Slots[i] {
+			mismatches = append(mismatches, SlotMismatch{Slot: i + 1, Expected: want, Actual: actual.Slots[i]})
+		}
+	}
+	return mismatches, nil
+}
+
+// validExpectedMap은 기대 맵 형식을 확인 (빈 문자열은 비교 안 함)
+func validExpectedMap(expected string) error {
+	expected = strings.TrimSpace(expected)
+	if expected == "" {
+		return nil
+	}
+	if !validSlotMapSize(len(expected)) {
+		return fmt.Errorf("기대 맵 길이가 올바르지 않습니다: %d (25 또는 13)", len(expected))
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package EFEMTest
+
+import (
+	. "ProtocolNexus/backend"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SystemConfigVersion은 현재 시스템 설정 파일 버전 (형식이 바뀌면 올리고 LoadSystem에서 변환)
+const SystemConfigVersion = 1
+
+// DeviceConfig는 장비 하나의 이름과 연결 설정
+type DeviceConfig struct {
+	Name       string        `json:"name"` // LP1, ALG1 ... (이름 앞부분으로 드라이버를 정함)
+	Connection SessionConfig `json:"connection"`
+}
+
+// RobotConfig는 로봇 연결 설정과 스테이션 테이블 (비어 있으면 DefaultWTRStations)
+type RobotConfig struct {
+	Name       string        `json:"name"`
+	Connection SessionConfig `json:"connection"`
+	Stations   []WTRStation  `json:"stations"`
+}
+
+// SystemConfig는 EFEM 시스템 정의 (System 폴더의 <Name>.json)
+type SystemConfig struct {
+	Version   int            `json:"version"`
+	Name      string         `json:"name"`
+	Loadports []DeviceConfig `json:"loadports"`
+	Aligners  []DeviceConfig `json:"aligners"`
+	Robots    []RobotConfig  `json:"robots"`
+	// Sequences는 이 시스템에서 실행할 수 있는 사이클 시퀀스 (Sequence 폴더의 파일 이름)
+	Sequences []string `json:"sequences"`
+}
+
+var (
+	activeSystemMu sync.Mutex
+	activeSystem   *SystemConfig // 마지막으로 연결한 시스템 (사이클 테스트 시퀀스 제한에 사용)
+)
+
+// SetActiveSystem은 장비를 연결한 시스템을 기록
+func SetActiveSystem(cfg SystemConfig) {
+	activeSystemMu.Lock()
+	activeSystem = &cfg
+	activeSystemMu.Unlock()
+}
+
+// checkActiveSequence는 시퀀스 이름이 연결한 시스템의 sequences에 있는지 확인하고 시스템 이름을 반환
+func checkActiveSequence(sequence string) (string, error) {
+	activeSystemMu.Lock()
+	cfg := activeSystem
+	activeSystemMu.Unlock()
+	if cfg == nil {
+		return "", fmt.Errorf("연결된 시스템이 없습니다")
+	}
+	for _, seq := range cfg.Sequences {
+		if seq == sequence {
+			return cfg.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%s 시스템에서 허용하지 않는 시퀀스입니다: %s", cfg.Name, sequence)
+}
+
+// NewSystemConfig는 4포트 EFEM 기본 구성 (장비는 로컬 TCP 포트, 시퀀스는 시스템 이름과 같은 파일)
+func NewSystemConfig(name string) SystemConfig {
+	device := func(name string, port int) DeviceConfig {
+		return DeviceConfig{Name: name, Connection: SessionConfig{Type: "tcp", Address: fmt.Sprintf("127.0.0.1:%d", port)}}
+	}
+	return SystemConfig{
+		Version:   SystemConfigVersion,
+		Name:      name,
+		Loadports: []DeviceConfig{device("LP1", 5001), device("LP2", 5002), device("LP3", 5003), device("LP4", 5004)},
+		Aligners:  []DeviceConfig{device("ALG1", 5011), device("ALG2", 5012)},
+		Robots: []RobotConfig{{Name: "WTR1", Connection: SessionConfig{Type: "tcp", Address: "127.0.0.1:5021"},
+			Stations: append([]WTRStation(nil), DefaultWTRStations...)}},
+		Sequences: []string{name},
+	}
+}
+
+// validFileName은 파일 이름으로 쓸 수 있는지 확인
+func validFileName(kind, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%s 이름이 없습니다", kind)
+	}
+	if strings.ContainsAny(name, `\/:*?"<>|`) || name == "." || name == ".." {
+		return fmt.Errorf("%s 이름에 사용할 수 없는 문자가 있습니다: %q", kind, name)
+	}
+	return nil
+}
+
+// Validate는 시스템 설정을 확인 (장비 이름 중복/접두어, 통신 방식, 스테이션 테이블, 시퀀스 이름)
+func (cfg SystemConfig) Validate() error {
+	if err := validFileName("시스템", cfg.Name); err != nil {
+		return err
+	}
+	if cfg.Version != SystemConfigVersion {
+		return fmt.Errorf("%s 지원하지 않는 설정 버전입니다: %d (현재 %d)", cfg.Name, cfg.Version, SystemConfigVersion)
+	}
+
+	types := make(map[string]bool)
+	for _, t := range TransportTypes() {
+		types[t] = true
+	}
+	names := make(map[string]bool)
+	checkDevice := func(prefix, name string, conn SessionConfig) error {
+		if !strings.HasPrefix(name, prefix) {
+			return fmt.Errorf("%s 장비 이름은 %s로 시작해야 합니다: %q", cfg.Name, prefix, name)
+		}
+		if names[name] {
+			return fmt.Errorf("%s 장비 이름이 중복되었습니다: %s", cfg.Name, name)
+		}
+		names[name] = true
+		if !types[conn.Type] {
+			return fmt.Errorf("%s %s 지원하지 않는 통신 방식입니다: %q", cfg.Name, name, conn.Type)
+		}
+		return nil
+	}
+
+	for _, d := range cfg.Loadports {
+		if err := checkDevice("LP", d.Name, d.Connection); err != nil {
+			return err
+		}
+	}
+	for _, d := range cfg.Aligners {
+		if err := checkDevice("ALG", d.Name, d.Connection); err != nil {
+			return err
+		}
+	}
+	for _, r := range cfg.Robots {
+		if err := checkDevice("WTR", r.Name, r.Connection); err != nil {
+			return err
+		}
+		if err := validateStations(r.Stations); err != nil {
+			return fmt.Errorf("%s %s %v", cfg.Name, r.Name, err)
+		}
+	}
+
+	sequences := make(map[string]bool)
+	for _, seq := range cfg.Sequences {
+		if err := validFileName("시퀀스", seq); err != nil {
+			return fmt.Errorf("%s %v", cfg.Name, err)
+		}
+		if sequences[seq] {
+			return fmt.Errorf("%s 시퀀스가 중복되었습니다: %s", cfg.Name, seq)
+		}
+		sequences[seq] = true
+	}
+	return nil
+}
+
+// validateStations는 스테이션 이름/코드 중복과 슬롯 수를 확인
+func validateStations(stations []WTRStation) error {
+	names := make(map[string]bool)
+	codes := make(map[int]bool)
+	for _, st := range stations {
+		key := strings.ToUpper(strings.TrimSpace(st.Name))
+		switch {
+		case key == "":
+			return fmt.Errorf("스테이션 이름이 없습니다")
+		case names[key]:
+			return fmt.Errorf("스테이션 이름이 중복되었습니다: %s", st.Name)
+		case st.Code <= 0 || codes[st.Code]:
+			return fmt.Errorf("%s 스테이션 코드가 올바르지 않거나 중복되었습니다: %d", st.Name, st.Code)
+		case st.Slots <= 0:
+			return fmt.Errorf("%s 슬롯 수가 올바르지 않습니다: %d", st.Name, st.Slots)
+		}
+		names[key], codes[st.Code] = true, true
+	}
+	return nil
+}
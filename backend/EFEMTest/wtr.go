@@ -0,0 +1,303 @@
+package EFEMTest
+
+import (
+	. "ProtocolNexus/backend"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WTR 명령 코드 (로드포트와 같은 ASCII 방식)
+const (
+	wtrCmdGet    = "GETW" // MOV: 웨이퍼 가져오기, 인자 == "스테이션코드,슬롯,핸드" (예: "01,05,A")
+	wtrCmdPut    = "PUTW" // MOV: 웨이퍼 놓기, 인자는 GETW와 같음
+	wtrCmdOrigin = "ORGN" // MOV: 원점 복귀
+	wtrCmdReset  = "RSET" // SET: 오류 해제
+)
+
+const (
+	wtrMoveTimeout  = 60 * time.Second
+	wtrStatusLength = 9
+	wtrLogLimit     = 200 // 로봇별로 보관하는 로그 줄 수
+)
+
+// WTRHands는 사용할 수 있는 핸드 (상/하 엔드이펙터)
+var WTRHands = []string{"A", "B"}
+
+// WTRStation은 로봇이 접근하는 스테이션 (Stage 이름 -> 장비 스테이션 코드, 슬롯 수)
+type WTRStation struct {
+	Name  string `json:"name"`
+	Code  int    `json:"code"`
+	Slots int    `json:"slots"`
+}
+
+// DefaultWTRStations는 4포트 EFEM 기본 스테이션 테이블
+var DefaultWTRStations = []WTRStation{
+	{Name: "LP1", Code: 1, Slots: 25},
+	{Name: "LP2", Code: 2, Slots: 25},
+	{Name: "LP3", Code: 3, Slots: 25},
+	{Name: "LP4", Code: 4, Slots: 25},
+	{Name: "ALG1", Code: 11, Slots: 1},
+	{Name: "ALG2", Code: 12, Slots: 1},
+}
+
+// WTRStatus는 GET:STAS 응답(9자리)을 해석한 상태
+// 상태 자리: [0] 장비 "0"/"A"/"E", [1] 원점 완료, [2] 동작 중, [3] 서보 ON, [4] 핸드 A 웨이퍼, [5] 핸드 B 웨이퍼, [6] 소프트 리밋 초과, [7:9] 오류 코드
+type WTRStatus struct {
+	Name      string `json:"name"`
+	Connected bool   `json:"connected"`
+	Run       bool   `json:"run"`
+	Stop      bool   `json:"stop"`
+	Origin    bool   `json:"origin"`
+	Overrun   bool   `json:"overrun"`
+	Alarm     bool   `json:"alarm"`
+	ErrorCode string `json:"errorCode"`
+	Servo     bool   `json:"servo"`
+	HandA     bool   `json:"handA"` // 핸드 위 웨이퍼 있음
+	HandB     bool   `json:"handB"`
+	Raw       string `json:"raw"`
+}
+
+// WTRLog는 로봇별 동작 로그 한 줄
+type WTRLog struct {
+	Name    string `json:"name"`
+	Time    string `json:"time"`
+	Level   string `json:"level"` // INFO, ERRO
+	Message string `json:"message"`
+}
+
+// WTREventHandler는 상태가 바뀔 때마다, WTRLogHandler는 로그가 쌓일 때마다 호출
+type WTREventHandler func(status WTRStatus)
+type WTRLogHandler func(log WTRLog)
+
+func parseWTRStatus(name, raw string) (WTRStatus, error) {
+	if len(raw) < wtrStatusLength {
+		return WTRStatus{}, fmt.Errorf("%s 상태 길이가 올바르지 않습니다: %q", name, raw)
+	}
+	return WTRStatus{
+		Name:      name,
+		Connected: true,
+		Run:       raw[2] == '1',
+		Stop:      raw[2] == '0',
+		Origin:    raw[1] == '1',
+		Overrun:   raw[6] == '1',
+		Alarm:     raw[0] != '0',
+		ErrorCode: raw[7:9],
+		Servo:     raw[3] == '1',
+		HandA:     raw[4] == '1',
+		HandB:     raw[5] == '1',
+		Raw:       raw,
+	}, nil
+}
+
+// WTR은 장비 세션 위에서 동작하는 웨이퍼 이송 로봇 드라이버
+type WTR struct {
+	*asciiDevice
+	onStatus WTREventHandler
+	onLog    WTRLogHandler
+	stations []WTRStation
+	status   WTRStatus
+	logs     []WTRLog
+	statusMu sync.Mutex
+}
+
+var (
+	wtrs   = make(map[string]*WTR)
+	wtrsMu sync.Mutex
+)
+
+// ConnectWTR은 로봇(WTR1~WTR3) 세션을 열고 상태 폴링을 시작 (스테이션 테이블은 DefaultWTRStations)
+func ConnectWTR(name string, cfg SessionConfig, onEvent EventHandler, onStatus WTREventHandler, onLog WTRLogHandler) (*WTR, error) {
+	DisconnectWTR(name)
+
+	device, err := connectASCIIDevice(name, cfg, onEvent)
+	if err != nil {
+		return nil, err
+	}
+	robot := &WTR{asciiDevice: device, onStatus: onStatus, onLog: onLog, stations: DefaultWTRStations}
+
+	wtrsMu.Lock()
+	wtrs[name] = robot
+	wtrsMu.Unlock()
+
+	robot.log("INFO", fmt.Sprintf("%s 연결", cfg.Address))
+	go robot.poll(func() error {
+		_, err := robot.ReadStatus()
+		return err
+	})
+	return robot, nil
+}
+
+// DisconnectWTR은 폴링을 멈추고 세션을 닫음
+func DisconnectWTR(name string) error {
+	wtrsMu.Lock()
+	robot, ok := wtrs[name]
+	delete(wtrs, name)
+	wtrsMu.Unlock()
+	if ok {
+		return robot.close()
+	}
+	return DisconnectDevice(name)
+}
+
+// WTRByName은 연결된 로봇 드라이버를 찾음
+func WTRByName(name string) (*WTR, error) {
+	wtrsMu.Lock()
+	defer wtrsMu.Unlock()
+	robot, ok := wtrs[name]
+	if !ok {
+		return nil, fmt.Errorf("%s 로봇이 연결되어 있지 않습니다", name)
+	}
+	return robot, nil
+}
+
+// SetStations는 스테이션 테이블을 바꿈
+func (w *WTR) SetStations(stations []WTRStation) {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+	w.stations = stations
+}
+
+// Stations는 현재 스테이션 테이블
+func (w *WTR) Stations() []WTRStation {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+	return append([]WTRStation(nil), w.stations...)
+}
+
+// Get은 stage의 slot(1부터)에서 hand로 웨이퍼를 가져옴
+func (w *WTR) Get(stage string, slot int, hand string) error {
+	return w.transfer("GET", wtrCmdGet, stage, slot, hand)
+}
+
+// Put은 hand의 웨이퍼를 stage의 slot(1부터)에 놓음
+func (w *WTR) Put(stage string, slot int, hand string) error {
+	return w.transfer("PUT", wtrCmdPut, stage, slot, hand)
+}
+
+// Origin은 원점 복귀
+func (w *WTR) Origin() error {
+	return w.run("ORIGIN", "MOV", wtrCmdOrigin, "", true)
+}
+
+// ResetError는 복구 가능한 오류를 해제
+func (w *WTR) ResetError() error {
+	return w.run("RESET", "SET", wtrCmdReset, "", false)
+}
+
+// Status는 마지막으로 읽은 상태
+func (w *WTR) Status() WTRStatus {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+	return w.status
+}
+
+// Logs는 보관 중인 동작 로그 (최근 wtrLogLimit줄)
+func (w *WTR) Logs() []WTRLog {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+	return append([]WTRLog(nil), w.logs...)
+}
+
+// ReadStatus는 상태를 즉시 읽고 바뀌었으면 알림
+func (w *WTR) ReadStatus() (WTRStatus, error) {
+	raw, err := w.command("GET", asciiStatusCode, "", false, 0)
+	if err != nil {
+		w.update(WTRStatus{Name: w.Name})
+		return WTRStatus{Name: w.Name}, err
+	}
+	status, err := parseWTRStatus(w.Name, raw)
+	if err != nil {
+		return status, err
+	}
+	w.update(status)
+	return status, nil
+}
+
+// transfer는 인자를 스테이션 테이블로 확인한 뒤 GET/PUT 명령을 완료까지 실행
+func (w *WTR) transfer(op, code, stage string, slot int, hand string) error {
+	station, err := w.station(stage)
+	if err == nil && (slot < 1 || slot > station.Slots) {
+		err = fmt.Errorf("%s 슬롯 범위를 벗어났습니다: %d (1~%d)", station.Name, slot, station.Slots)
+	}
+	hand = strings.ToUpper(strings.TrimSpace(hand))
+	if err == nil && !validHand(hand) {
+		err = fmt.Errorf("잘못된 핸드입니다: %q (%s)", hand, strings.Join(WTRHands, "/"))
+	}
+	if err != nil {
+		w.log("ERRO", fmt.Sprintf("%s 실패: %v", op, err))
+		return fmt.Errorf("%s %v", w.Name, err)
+	}
+
+	arg := fmt.Sprintf("%02d,%02d,%s", station.Code, slot, hand)
+	return w.run(fmt.Sprintf("%s %s slot %d hand %s", op, station.Name, slot, hand), "MOV", code, arg, true)
+}
+
+// run은 명령을 실행하고 시작/완료/실패를 로그로 남김
+func (w *WTR) run(op, prefix, code, arg string, complete bool) error {
+	w.log("INFO", op+" 시작")
+	start := time.Now()
+	if _, err := w.command(prefix, code, arg, complete, wtrMoveTimeout); err != nil {
+		w.log("ERRO", fmt.Sprintf("%s 실패: %v", op, err))
+		return err
+	}
+	w.log("INFO", fmt.Sprintf("%s 완료 (%.1fs)", op, time.Since(start).Seconds()))
+	return nil
+}
+
+func (w *WTR) station(stage string) (WTRStation, error) {
+	stage = strings.TrimSpace(stage)
+	for _, station := range w.Stations() {
+		if strings.EqualFold(station.Name, stage) {
+			return station, nil
+		}
+	}
+	return WTRStation{}, fmt.Errorf("스테이션 테이블에 없는 Stage입니다: %q", stage)
+}
+
+func validHand(hand string) bool {
+	for _, h := range WTRHands {
+		if h == hand {
+			return true
+		}
+	}
+	return false
+}
+
+// update는 상태를 저장하고 바뀌었으면 알림, 알람 발생/해제는 로그로 남김
+func (w *WTR) update(status WTRStatus) {
+	w.statusMu.Lock()
+	prev := w.status
+	changed := prev != status
+	w.status = status
+	w.statusMu.Unlock()
+	if !changed {
+		return
+	}
+
+	switch {
+	case status.Connected && status.Alarm && (!prev.Alarm || prev.ErrorCode != status.ErrorCode):
+		w.log("ERRO", fmt.Sprintf("알람 발생 (오류 코드 %s)", status.ErrorCode))
+	case status.Connected && prev.Alarm && !status.Alarm:
+		w.log("INFO", "알람 해제")
+	case !status.Connected && prev.Connected:
+		w.log("ERRO", "상태 응답 없음")
+	}
+	if w.onStatus != nil {
+		w.onStatus(status)
+	}
+}
+
+func (w *WTR) log(level, message string) {
+	entry := WTRLog{Name: w.Name, Time: time.Now().Format("15:04:05.000"), Level: level, Message: message}
+	w.statusMu.Lock()
+	w.logs = append(w.logs, entry)
+	if len(w.logs) > wtrLogLimit {
+		w.logs = w.logs[len(w.logs)-wtrLogLimit:]
+	}
+	w.statusMu.Unlock()
+	if w.onLog != nil {
+		w.onLog(entry)
+	}
+}
@@ -0,0 +1,144 @@
+package Modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Modbus 함수 코드
+const (
+	fcReadCoils              byte = 0x01
+	fcReadDiscreteInputs     byte = 0x02
+	fcReadHoldingRegisters   byte = 0x03
+	fcReadInputRegisters     byte = 0x04
+	fcWriteSingleCoil        byte = 0x05
+	fcWriteSingleRegister    byte = 0x06
+	fcWriteMultipleCoils     byte = 0x0F
+	fcWriteMultipleRegisters byte = 0x10
+)
+
+// 예외 코드 이름 (응답 함수 코드에 0x80이 붙으면 다음 바이트가 예외 코드)
+var exceptionNames = map[byte]string{
+	0x01: "잘못된 함수 (Illegal Function)",
+	0x02: "잘못된 데이터 주소 (Illegal Data Address)",
+	0x03: "잘못된 데이터 값 (Illegal Data Value)",
+	0x04: "슬레이브 장치 오류 (Slave Device Failure)",
+	0x05: "처리 중 (Acknowledge)",
+	0x06: "슬레이브 장치 바쁨 (Slave Device Busy)",
+	0x08: "메모리 패리티 오류 (Memory Parity Error)",
+	0x0A: "게이트웨이 경로 없음 (Gateway Path Unavailable)",
+	0x0B: "게이트웨이 대상 응답 없음 (Gateway Target Failed to Respond)",
+}
+
+// ExceptionError는 슬레이브가 돌려준 예외 응답
+type ExceptionError struct {
+	Function byte
+	Code     byte
+}
+
+func (e *ExceptionError) Error() string {
+	name, ok := exceptionNames[e.Code]
+	if !ok {
+		name = "알 수 없는 예외"
+	}
+	return fmt.Sprintf("Modbus 예외 0x%02X: %s (함수 0x%02X)", e.Code, name, e.Function)
+}
+
+// crc16은 Modbus RTU CRC (다항식 0xA001, 초기값 0xFFFF, 하위 바이트 먼저 전송)
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// rtuADU는 "장치 주소 + PDU + CRC"
+func rtuADU(unit byte, pdu []byte) []byte {
+	adu := append([]byte{unit}, pdu...)
+	return binary.LittleEndian.AppendUint16(adu, crc16(adu))
+}
+
+// tcpADU는 MBAP 헤더(트랜잭션 ID, 프로토콜 0, 길이, 유닛 ID) + PDU
+func tcpADU(transaction uint16, unit byte, pdu []byte) []byte {
+	adu := make([]byte, 7, 7+len(pdu))
+	binary.BigEndian.PutUint16(adu[0:2], transaction)
+	binary.BigEndian.PutUint16(adu[4:6], uint16(1+len(pdu)))
+	adu[6] = unit
+	return append(adu, pdu...)
+}
+
+// rtuFrameLength는 수신 버퍼 앞부분으로 응답 프레임 길이를 계산 (아직 알 수 없으면 0)
+func rtuFrameLength(buf []byte) (int, error) {
+	if len(buf) < 2 {
+		return 0, nil
+	}
+	fc := buf[1]
+	switch {
+	case fc&0x80 != 0:
+		return 5, nil
+	case fc >= fcReadCoils && fc <= fcReadInputRegisters:
+		if len(buf) < 3 {
+			return 0, nil
+		}
+		return 3 + int(buf[2]) + 2, nil
+	case fc == fcWriteSingleCoil || fc == fcWriteSingleRegister || fc == fcWriteMultipleCoils || fc == fcWriteMultipleRegisters:
+		return 8, nil
+	}
+	return 0, fmt.Errorf("알 수 없는 응답 함수 코드 0x%02X", fc)
+}
+
+// tcpFrameLength는 MBAP 길이 필드로 응답 프레임 길이를 계산 (아직 알 수 없으면 0)
+func tcpFrameLength(buf []byte) (int, error) {
+	if len(buf) < 6 {
+		return 0, nil
+	}
+	length := int(binary.BigEndian.Uint16(buf[4:6]))
+	if length < 2 || length > 254 {
+		return 0, fmt.Errorf("잘못된 MBAP 길이 %d", length)
+	}
+	return 6 + length, nil
+}
+
+// checkResponse는 응답 PDU의 함수 코드를 확인하고 예외면 ExceptionError를 반환
+func checkResponse(fc byte, pdu []byte) error {
+	if len(pdu) == 0 {
+		return fmt.Errorf("빈 응답입니다")
+	}
+	if pdu[0] == fc|0x80 {
+		if len(pdu) < 2 {
+			return fmt.Errorf("예외 응답이 짧습니다")
+		}
+		return &ExceptionError{Function: fc, Code: pdu[1]}
+	}
+	if pdu[0] != fc {
+		return fmt.Errorf("응답 함수 코드 불일치 (요청 0x%02X, 응답 0x%02X)", fc, pdu[0])
+	}
+	return nil
+}
+
+// packBits는 코일 값을 LSB부터 바이트로 묶음
+func packBits(bits []bool) []byte {
+	data := make([]byte, (len(bits)+7)/8)
+	for i, on := range bits {
+		if on {
+			data[i/8] |= 1 << (i % 8)
+		}
+	}
+	return data
+}
+
+func unpackBits(data []byte, count int) []bool {
+	bits := make([]bool, count)
+	for i := range bits {
+		bits[i] = data[i/8]&(1<<(i%8)) != 0
+	}
+	return bits
+}
@@ -0,0 +1,376 @@
+package Modbus
+
+import (
+	. "ProtocolNexus/backend"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 레지스터 테이블 이름 (Request.Table)
+const (
+	TableCoil     = "coil"     // 0x 코일 (읽기/쓰기)
+	TableDiscrete = "discrete" // 1x 디지털 입력 (읽기)
+	TableInput    = "input"    // 3x 입력 레지스터 (읽기)
+	TableHolding  = "holding"  // 4x 보유 레지스터 (읽기/쓰기)
+)
+
+const defaultTimeout = time.Second
+
+// Config는 Modbus 마스터 설정
+// RTU는 보통 serial 연결 (terminal server면 tcp), TCP는 tcp 연결 (기본 포트 502)
+type Config struct {
+	Mode       string        `json:"mode"` // "rtu" 또는 "tcp"
+	Connection SessionConfig `json:"connection"`
+	TimeoutMs  int           `json:"timeoutMs"` // 응답 대기 (기본 1초)
+}
+
+// Request는 읽기/쓰기 요청 하나 (쓰기는 Bits 또는 Registers 길이가 개수)
+type Request struct {
+	Unit      int      `json:"unit"`  // 슬레이브 주소 (RTU 0 == 브로드캐스트, 쓰기만)
+	Table     string   `json:"table"` // TableCoil, TableDiscrete, TableInput, TableHolding
+	Address   int      `json:"address"`
+	Count     int      `json:"count"`
+	Bits      []bool   `json:"bits"`
+	Registers []uint16 `json:"registers"`
+}
+
+// Result는 읽기 결과 (코일/디지털 입력은 Bits, 레지스터는 Registers)
+type Result struct {
+	Bits      []bool   `json:"bits"`
+	Registers []uint16 `json:"registers"`
+}
+
+// Master는 Modbus RTU/TCP 마스터 하나 (세션은 바이너리 모드, 요청은 한 번에 하나)
+type Master struct {
+	Name      string
+	mode      string
+	timeout   time.Duration
+	sessionID string
+	unlisten  func()
+
+	reqMu       sync.Mutex
+	transaction uint16
+
+	mu     sync.Mutex
+	rxBuf  []byte
+	rxWake chan struct{}
+}
+
+var (
+	masters   = make(map[string]*Master)
+	mastersMu sync.Mutex
+)
+
+// Connect는 Modbus 세션을 열고 이름으로 등록 (송수신 프레임은 onEvent로 16진수 표시)
+func Connect(name string, cfg Config, onEvent EventHandler) (*Master, error) {
+	Disconnect(name)
+
+	if cfg.Mode != "rtu" && cfg.Mode != "tcp" {
+		return nil, fmt.Errorf("Modbus 모드는 rtu 또는 tcp여야 합니다: %s", cfg.Mode)
+	}
+	conn := cfg.Connection
+	conn.Binary = true
+	conn.TxTerminator = "NONE"
+	conn.Framing = FramingConfig{Mode: "none"}
+
+	sessionID, err := OpenSession(conn, onEvent)
+	if err != nil {
+		return nil, fmt.Errorf("%s 연결 실패: %v", name, err)
+	}
+	m := &Master{Name: name, mode: cfg.Mode, timeout: defaultTimeout, sessionID: sessionID, rxWake: make(chan struct{}, 1)}
+	if cfg.TimeoutMs > 0 {
+		m.timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+	}
+	if m.unlisten, err = ListenSession(sessionID, m.receive); err != nil {
+		CloseSession(sessionID)
+		return nil, err
+	}
+
+	mastersMu.Lock()
+	masters[name] = m
+	mastersMu.Unlock()
+	return m, nil
+}
+
+// Disconnect는 수신을 멈추고 세션을 닫음
+func Disconnect(name string) error {
+	mastersMu.Lock()
+	m, ok := masters[name]
+	delete(masters, name)
+	mastersMu.Unlock()
+	if !ok {
+		return nil
+	}
+	m.unlisten()
+	return CloseSession(m.sessionID)
+}
+
+// ByName은 연결된 마스터를 찾음
+func ByName(name string) (*Master, error) {
+	mastersMu.Lock()
+	defer mastersMu.Unlock()
+	m, ok := masters[name]
+	if !ok {
+		return nil, fmt.Errorf("%s Modbus 장치가 연결되어 있지 않습니다", name)
+	}
+	return m, nil
+}
+
+// Read는 테이블에 맞는 읽기 함수(0x01~0x04)를 보냄
+func (m *Master) Read(req Request) (Result, error) {
+	var result Result
+	var err error
+	switch req.Table {
+	case TableCoil:
+		result.Bits, err = m.readBits(fcReadCoils, req)
+	case TableDiscrete:
+		result.Bits, err = m.readBits(fcReadDiscreteInputs, req)
+	case TableHolding:
+		result.Registers, err = m.readRegisters(fcReadHoldingRegisters, req)
+	case TableInput:
+		result.Registers, err = m.readRegisters(fcReadInputRegisters, req)
+	default:
+		err = fmt.Errorf("알 수 없는 테이블입니다: %s", req.Table)
+	}
+	return result, err
+}
+
+// Write는 값이 하나면 단일 쓰기(0x05/0x06), 여러 개면 다중 쓰기(0x0F/0x10)
+func (m *Master) Write(req Request) error {
+	if err := checkAddress(req.Address); err != nil {
+		return err
+	}
+	if n := len(req.Bits) + len(req.Registers); req.Address+n > 0x10000 {
+		return fmt.Errorf("주소 범위를 벗어났습니다: %d+%d", req.Address, n)
+	}
+	switch req.Table {
+	case TableCoil:
+		return m.writeCoils(req)
+	case TableHolding:
+		return m.writeRegisters(req)
+	case TableDiscrete, TableInput:
+		return fmt.Errorf("%s 테이블은 읽기 전용입니다", req.Table)
+	}
+	return fmt.Errorf("알 수 없는 테이블입니다: %s", req.Table)
+}
+
+func (m *Master) readBits(fc byte, req Request) ([]bool, error) {
+	if err := checkRange(req, 2000); err != nil {
+		return nil, err
+	}
+	pdu, err := m.transact(req.Unit, readPDU(fc, req))
+	if err != nil {
+		return nil, err
+	}
+	if len(pdu) < 2 || int(pdu[1]) != (req.Count+7)/8 || len(pdu) != 2+int(pdu[1]) {
+		return nil, fmt.Errorf("응답 바이트 수 불일치 (%d개 요청)", req.Count)
+	}
+	return unpackBits(pdu[2:], req.Count), nil
+}
+
+func (m *Master) readRegisters(fc byte, req Request) ([]uint16, error) {
+	if err := checkRange(req, 125); err != nil {
+		return nil, err
+	}
+	pdu, err := m.transact(req.Unit, readPDU(fc, req))
+	if err != nil {
+		return nil, err
+	}
+	if len(pdu) < 2 || int(pdu[1]) != 2*req.Count || len(pdu) != 2+int(pdu[1]) {
+		return nil, fmt.Errorf("응답 바이트 수 불일치 (%d개 요청)", req.Count)
+	}
+	registers := make([]uint16, req.Count)
+	for i := range registers {
+		registers[i] = binary.BigEndian.Uint16(pdu[2+2*i:])
+	}
+	return registers, nil
+}
+
+func (m *Master) writeCoils(req Request) error {
+	var pdu []byte
+	switch n := len(req.Bits); {
+	case n == 1:
+		value := uint16(0x0000)
+		if req.Bits[0] {
+			value = 0xFF00
+		}
+		pdu = []byte{fcWriteSingleCoil, byte(req.Address >> 8), byte(req.Address), byte(value >> 8), byte(value)}
+	case n >= 2 && n <= 1968:
+		data := packBits(req.Bits)
+		pdu = []byte{fcWriteMultipleCoils, byte(req.Address >> 8), byte(req.Address), byte(n >> 8), byte(n), byte(len(data))}
+		pdu = append(pdu, data...)
+	default:
+		return fmt.Errorf("코일 쓰기 개수는 1~1968이어야 합니다: %d", n)
+	}
+	return m.write(req, pdu)
+}
+
+func (m *Master) writeRegisters(req Request) error {
+	var pdu []byte
+	switch n := len(req.Registers); {
+	case n == 1:
+		pdu = []byte{fcWriteSingleRegister, byte(req.Address >> 8), byte(req.Address)}
+		pdu = binary.BigEndian.AppendUint16(pdu, req.Registers[0])
+	case n >= 2 && n <= 123:
+		pdu = []byte{fcWriteMultipleRegisters, byte(req.Address >> 8), byte(req.Address), byte(n >> 8), byte(n), byte(2 * n)}
+		for _, v := range req.Registers {
+			pdu = binary.BigEndian.AppendUint16(pdu, v)
+		}
+	default:
+		return fmt.Errorf("레지스터 쓰기 개수는 1~123이어야 합니다: %d", n)
+	}
+	return m.write(req, pdu)
+}
+
+// write는 쓰기 요청을 보내고 응답이 요청의 주소/값(다중 쓰기는 주소/개수)을 되돌려주는지 확인
+func (m *Master) write(req Request, pdu []byte) error {
+	rsp, err := m.transact(req.Unit, pdu)
+	if err != nil || rsp == nil {
+		return err
+	}
+	if len(rsp) != 5 || string(rsp[1:5]) != string(pdu[1:5]) {
+		return fmt.Errorf("쓰기 응답 불일치 (% X)", rsp)
+	}
+	return nil
+}
+
+func readPDU(fc byte, req Request) []byte {
+	return []byte{fc, byte(req.Address >> 8), byte(req.Address), byte(req.Count >> 8), byte(req.Count)}
+}
+
+func checkAddress(address int) error {
+	if address < 0 || address > 0xFFFF {
+		return fmt.Errorf("잘못된 주소입니다: %d", address)
+	}
+	return nil
+}
+
+func checkRange(req Request, max int) error {
+	if err := checkAddress(req.Address); err != nil {
+		return err
+	}
+	if req.Count < 1 || req.Count > max {
+		return fmt.Errorf("읽기 개수는 1~%d이어야 합니다: %d", max, req.Count)
+	}
+	if req.Address+req.Count > 0x10000 {
+		return fmt.Errorf("주소 범위를 벗어났습니다: %d+%d", req.Address, req.Count)
+	}
+	return nil
+}
+
+// transact는 PDU를 ADU로 감싸 보내고 응답 PDU를 반환 (RTU 브로드캐스트는 응답 없이 nil)
+func (m *Master) transact(unit int, pdu []byte) ([]byte, error) {
+	if unit < 0 || unit > 255 {
+		return nil, fmt.Errorf("잘못된 슬레이브 주소입니다: %d", unit)
+	}
+	broadcast := m.mode == "rtu" && unit == 0
+	if broadcast && pdu[0] <= fcReadInputRegisters {
+		return nil, fmt.Errorf("브로드캐스트(주소 0)는 쓰기만 가능합니다")
+	}
+
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+
+	var adu []byte
+	if m.mode == "rtu" {
+		adu = rtuADU(byte(unit), pdu)
+	} else {
+		m.transaction++
+		adu = tcpADU(m.transaction, byte(unit), pdu)
+	}
+
+	m.mu.Lock()
+	m.rxBuf = nil // 이전 요청의 늦은 응답은 버림
+	m.mu.Unlock()
+	if err := SessionSend(m.sessionID, fmt.Sprintf("% X", adu)); err != nil {
+		return nil, err
+	}
+	if broadcast {
+		return nil, nil
+	}
+
+	deadline := time.Now().Add(m.timeout)
+	var rsp []byte
+	for {
+		frame, err := m.waitFrame(deadline)
+		if err != nil {
+			return nil, err
+		}
+		if m.mode == "tcp" && binary.BigEndian.Uint16(frame[0:2]) != m.transaction {
+			fmt.Printf("[%s] 이전 트랜잭션 응답 무시 (% X)\n", m.Name, frame[:7])
+			continue
+		}
+		if rsp, err = m.unwrap(frame, byte(unit)); err != nil {
+			return nil, err
+		}
+		break
+	}
+	if err := checkResponse(pdu[0], rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// waitFrame은 응답 프레임 하나가 모두 도착할 때까지 deadline까지 기다림
+func (m *Master) waitFrame(deadline time.Time) ([]byte, error) {
+	frameLength := tcpFrameLength
+	if m.mode == "rtu" {
+		frameLength = rtuFrameLength
+	}
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	for {
+		m.mu.Lock()
+		n, err := frameLength(m.rxBuf)
+		if err == nil && n > 0 && len(m.rxBuf) >= n {
+			frame := m.rxBuf[:n]
+			m.rxBuf = m.rxBuf[n:]
+			m.mu.Unlock()
+			return frame, nil
+		}
+		m.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-m.rxWake:
+		case <-timer.C:
+			return nil, fmt.Errorf("%s 응답 시간 초과 (%v)", m.Name, m.timeout)
+		}
+	}
+}
+
+// unwrap은 ADU를 확인하고 PDU를 꺼냄 (RTU는 CRC와 주소, TCP는 트랜잭션 ID와 유닛 ID)
+func (m *Master) unwrap(frame []byte, unit byte) ([]byte, error) {
+	if m.mode == "rtu" {
+		body := frame[:len(frame)-2]
+		if crc16(body) != binary.LittleEndian.Uint16(frame[len(frame)-2:]) {
+			return nil, fmt.Errorf("CRC 오류 (% X)", frame)
+		}
+		if frame[0] != unit {
+			return nil, fmt.Errorf("응답 슬레이브 주소 불일치 (요청 %d, 응답 %d)", unit, frame[0])
+		}
+		return body[1:], nil
+	}
+	if binary.BigEndian.Uint16(frame[2:4]) != 0 {
+		return nil, fmt.Errorf("MBAP 프로토콜 ID 오류 (% X)", frame[:7])
+	}
+	if frame[6] != unit {
+		return nil, fmt.Errorf("응답 유닛 ID 불일치 (요청 %d, 응답 %d)", unit, frame[6])
+	}
+	return frame[7:], nil
+}
+
+// receive는 세션 수신 조각을 버퍼에 쌓고 대기 중인 요청을 깨움
+func (m *Master) receive(message string) {
+	m.mu.Lock()
+	m.rxBuf = append(m.rxBuf, message...)
+	m.mu.Unlock()
+	select {
+	case m.rxWake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,170 @@
+package Simulator
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// TAS300 방식 ASCII 장비 응답 ("PREFIX:CODE[/ARG];" 수신 -> "ACK:CODE[/DETAIL];", MOV는 동작 후 "INF" 또는 "ABS")
+const (
+	simStatusCode = "STAS"
+	simTerminator = ";\r"
+)
+
+// asciiAction은 명령 하나에 대한 장비의 처리
+type asciiAction struct {
+	nak    string // 비어 있지 않으면 NAK 원인
+	detail string // ACK 뒤 내용 (GET 응답)
+	// complete는 MOV 동작이 끝날 때 상태를 바꾸고 INF 내용을 반환, 오류면 ABS 원인
+	complete func() (string, error)
+}
+
+// asciiModel은 장비 종류별 상태와 명령 처리 (asciiSim.mu를 잡은 상태에서 호출)
+type asciiModel interface {
+	status(run bool, alarm string) string
+	handle(prefix, code, arg string) asciiAction
+}
+
+// asciiSim은 ASCII 장비 공통 동작 (동작 중 거부, 알람, 고장 주입, 지연 후 완료 보고)
+type asciiSim struct {
+	srv        *simServer
+	model      asciiModel
+	resetCodes []string // 알람을 해제하는 명령 코드
+	moving     string   // 동작 중인 명령 코드
+	mu         sync.Mutex
+}
+
+func (a *asciiSim) serve(c *simConn) {
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return
+		}
+		if reply := a.receive(c, strings.TrimSpace(line)); reply != "" {
+			c.write(reply + simTerminator)
+		}
+	}
+}
+
+// receive는 명령 하나를 처리하고 즉시 보낼 응답을 반환 (MOV 완료 보고는 나중에 따로 보냄)
+func (a *asciiSim) receive(c *simConn, line string) string {
+	prefix, rest, ok := strings.Cut(strings.TrimSuffix(line, ";"), ":")
+	if !ok {
+		return ""
+	}
+	code, arg, _ := strings.Cut(rest, "/")
+	cfg := a.srv.config()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if prefix == "GET" && code == simStatusCode {
+		return "ACK:" + code + "/" + a.model.status(a.moving != "", cfg.AlarmCode)
+	}
+
+	reset := a.isReset(code)
+	if prefix == "MOV" {
+		switch {
+		case cfg.Fault == "noack":
+			return ""
+		case cfg.Fault == "nak":
+			return "NAK:" + code + "/SIMFAULT"
+		case a.moving != "":
+			return "NAK:" + code + "/BUSY"
+		case cfg.AlarmCode != "" && !reset:
+			return "NAK:" + code + "/ALARM"
+		}
+	}
+
+	action := a.model.handle(prefix, code, arg)
+	if action.nak != "" {
+		return "NAK:" + code + "/" + action.nak
+	}
+	if reset {
+		a.clearAlarm()
+	}
+	if action.complete == nil {
+		return join("ACK", code, action.detail)
+	}
+
+	a.moving = code
+	go a.finish(c, code, action.complete, cfg.Fault)
+	return join("ACK", code, action.detail)
+}
+
+// finish는 동작 시간 뒤 완료(INF) 또는 실패(ABS)를 보고
+func (a *asciiSim) finish(c *simConn, code string, complete func() (string, error), fault string) {
+	time.Sleep(a.srv.delay())
+
+	a.mu.Lock()
+	var reply string
+	switch fault {
+	case "abs":
+		reply = "ABS:" + code + "/SIMFAULT"
+	case "noinf":
+	default:
+		if detail, err := complete(); err != nil {
+			reply = "ABS:" + code + "/" + err.Error()
+		} else {
+			reply = join("INF", code, detail)
+		}
+	}
+	a.moving = ""
+	a.mu.Unlock()
+
+	if reply != "" {
+		c.write(reply + simTerminator)
+	}
+}
+
+func (a *asciiSim) isReset(code string) bool {
+	for _, c := range a.resetCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *asciiSim) clearAlarm() {
+	a.srv.mu.Lock()
+	a.srv.cfg.AlarmCode = ""
+	a.srv.mu.Unlock()
+}
+
+func join(kind, code, detail string) string {
+	if detail == "" {
+		return kind + ":" + code
+	}
+	return kind + ":" + code + "/" + detail
+}
+
+// alarmField는 상태 문자열의 2자리 오류 코드
+func alarmField(alarm string) string {
+	switch {
+	case alarm == "":
+		return "00"
+	case len(alarm) == 1:
+		return "0" + alarm
+	}
+	return alarm[:2]
+}
+
+// equipmentField는 상태 문자열의 장비 상태 자리 ("0" 정상, "A" 알람)
+func equipmentField(alarm string) byte {
+	if alarm == "" {
+		return '0'
+	}
+	return 'A'
+}
+
+func flag(on bool) byte {
+	if on {
+		return '1'
+	}
+	return '0'
+}
+
+// unknownCommand는 지원하지 않는 명령에 대한 NAK
+var unknownCommand = asciiAction{nak: "UNKNOWN"}
@@ -0,0 +1,183 @@
+package Simulator
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// loadportModel은 FOUP이 올려진 로드포트 (상태 20자리, 매핑 결과는 SimConfig.SlotMap)
+type loadportModel struct {
+	srv                             *simServer
+	origin, clamped, docked, mapped bool
+	doorOpen                        bool
+}
+
+func newLoadportSim(srv *simServer) simHandler {
+	return &asciiSim{srv: srv, model: &loadportModel{srv: srv, origin: true}, resetCodes: []string{"RSET"}}
+}
+
+func (m *loadportModel) status(run bool, alarm string) string {
+	s := []byte(strings.Repeat("0", 20))
+	s[0] = equipmentField(alarm)
+	s[2] = flag(m.origin)
+	s[3] = flag(run)
+	copy(s[4:6], alarmField(alarm))
+	s[6] = '1' // FOUP 있음
+	s[7] = flag(m.clamped)
+	s[10] = flag(!m.doorOpen) // "0"이면 열림
+	s[11] = '1'               // "0"이면 웨이퍼 돌출
+	s[13] = flag(m.docked)
+	s[17] = flag(m.mapped)
+	return string(s)
+}
+
+func (m *loadportModel) handle(prefix, code, arg string) asciiAction {
+	switch prefix + ":" + code {
+	case "GET:MAPR":
+		if !m.mapped {
+			return asciiAction{nak: "NOTMAPPED"}
+		}
+		slotMap := m.srv.config().SlotMap
+		if slotMap == "" {
+			slotMap = strings.Repeat("1", 25)
+		}
+		return asciiAction{detail: slotMap}
+	case "MOV:CLOD":
+		return asciiAction{complete: func() (string, error) {
+			m.clamped, m.docked, m.doorOpen, m.mapped = true, true, true, true
+			return "", nil
+		}}
+	case "MOV:CULD":
+		return asciiAction{complete: func() (string, error) {
+			m.clamped, m.docked, m.doorOpen, m.mapped = false, false, false, false
+			return "", nil
+		}}
+	case "MOV:ORGN":
+		return asciiAction{complete: func() (string, error) {
+			m.origin = true
+			return "", nil
+		}}
+	case "SET:RSET":
+		return asciiAction{}
+	}
+	return unknownCommand
+}
+
+// alignerModel은 웨이퍼가 올려진 얼라이너 (상태 8자리)
+type alignerModel struct {
+	origin, wafer, vacuum, aligned bool
+}
+
+func newAlignerSim(srv *simServer) simHandler {
+	return &asciiSim{srv: srv, model: &alignerModel{origin: true, wafer: true}, resetCodes: []string{"INIT"}}
+}
+
+func (m *alignerModel) status(run bool, alarm string) string {
+	s := []byte(strings.Repeat("0", 8))
+	s[0] = equipmentField(alarm)
+	s[1] = flag(m.origin)
+	s[2] = flag(run)
+	s[3] = flag(m.wafer)
+	s[4] = flag(m.vacuum)
+	copy(s[5:7], alarmField(alarm))
+	s[7] = flag(m.aligned)
+	return string(s)
+}
+
+func (m *alignerModel) handle(prefix, code, arg string) asciiAction {
+	switch prefix + ":" + code {
+	case "MOV:ALGN":
+		angle, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return asciiAction{nak: "PARAM"}
+		}
+		return asciiAction{complete: func() (string, error) {
+			if !m.wafer {
+				return "", errors.New("NOWAFER")
+			}
+			m.aligned = true
+			return fmt.Sprintf("%.2f,%.3f,%.3f", angle, rand.Float64()*0.4-0.2, rand.Float64()*0.4-0.2), nil
+		}}
+	case "MOV:INIT":
+		return asciiAction{complete: func() (string, error) {
+			m.origin, m.aligned = true, false
+			return "", nil
+		}}
+	case "SET:VACN":
+		m.vacuum = true
+		return asciiAction{}
+	case "SET:VACF":
+		m.vacuum = false
+		return asciiAction{}
+	}
+	return unknownCommand
+}
+
+// robotModel은 핸드 A/B를 가진 웨이퍼 이송 로봇 (상태 9자리)
+type robotModel struct {
+	origin bool
+	hands  map[string]bool // 핸드 -> 웨이퍼 있음
+}
+
+func newRobotSim(srv *simServer) simHandler {
+	return &asciiSim{srv: srv, model: &robotModel{origin: true, hands: map[string]bool{"A": false, "B": false}}, resetCodes: []string{"RSET"}}
+}
+
+func (m *robotModel) status(run bool, alarm string) string {
+	s := []byte(strings.Repeat("0", 9))
+	s[0] = equipmentField(alarm)
+	s[1] = flag(m.origin)
+	s[2] = flag(run)
+	s[3] = '1' // 서보 ON
+	s[4] = flag(m.hands["A"])
+	s[5] = flag(m.hands["B"])
+	copy(s[7:9], alarmField(alarm))
+	return string(s)
+}
+
+func (m *robotModel) handle(prefix, code, arg string) asciiAction {
+	switch prefix + ":" + code {
+	case "MOV:GETW", "MOV:PUTW":
+		hand, ok := m.transferHand(arg)
+		if !ok {
+			return asciiAction{nak: "PARAM"}
+		}
+		get := code == "GETW"
+		return asciiAction{complete: func() (string, error) {
+			switch {
+			case get && m.hands[hand]:
+				return "", errors.New("HANDFULL")
+			case !get && !m.hands[hand]:
+				return "", errors.New("NOWAFER")
+			}
+			m.hands[hand] = get
+			return "", nil
+		}}
+	case "MOV:ORGN":
+		return asciiAction{complete: func() (string, error) {
+			m.origin = true
+			return "", nil
+		}}
+	case "SET:RSET":
+		return asciiAction{}
+	}
+	return unknownCommand
+}
+
+// transferHand는 "스테이션,슬롯,핸드" 인자를 확인하고 핸드를 반환
+func (m *robotModel) transferHand(arg string) (string, bool) {
+	fields := strings.Split(arg, ",")
+	if len(fields) != 3 {
+		return "", false
+	}
+	for _, f := range fields[:2] {
+		if n, err := strconv.Atoi(f); err != nil || n <= 0 {
+			return "", false
+		}
+	}
+	_, ok := m.hands[fields[2]]
+	return fields[2], ok
+}
@@ -0,0 +1,81 @@
+package Simulator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GPL 콘솔 기본 응답 (명령은 대소문자 구분 없음, SimConfig.Responses로 덮어쓰거나 추가)
+var gplResponses = map[string]string{
+	"version":     "GPL 4.2F1 (Simulator)",
+	"where":       "X 300.000 Y 0.000 Z 100.000 Yaw 0.000 Pitch 90.000 Roll 180.000",
+	"wherej":      "J1 100.000 J2 0.000 J3 90.000 J4 0.000 J5 0.000",
+	"show thread": "No threads running",
+	"attach":      "",
+	"release":     "",
+}
+
+// gplSim은 Password:/GPL: 로그인을 하는 GPL Telnet 콘솔 (입력 줄을 에코하고 응답 뒤에 프롬프트)
+type gplSim struct {
+	srv *simServer
+}
+
+func (g *gplSim) serve(c *simConn) {
+	if err := g.login(c); err != nil {
+		return
+	}
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		if strings.EqualFold(command, "exit") || strings.EqualFold(command, "quit") {
+			c.write(command + "\r\n")
+			return
+		}
+
+		c.write(command + "\r\n")
+		time.Sleep(g.srv.delay())
+		if response := g.respond(command); response != "" {
+			c.write(strings.ReplaceAll(response, "\n", "\r\n") + "\r\n")
+		}
+		c.write("GPL: ")
+	}
+}
+
+// login은 비밀번호가 맞을 때까지 Password:를 다시 보냄
+func (g *gplSim) login(c *simConn) error {
+	password := g.srv.config().Password
+	if password == "" {
+		password = "Help"
+	}
+	c.write("Welcome to GPL Simulator\r\nPassword: ")
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(line) == password {
+			return c.write("\r\nGPL: ")
+		}
+		c.write("\r\n*Invalid password*\r\nPassword: ")
+	}
+}
+
+func (g *gplSim) respond(command string) string {
+	if command == "" {
+		return ""
+	}
+	key := strings.ToLower(command)
+	for k, v := range g.srv.config().Responses {
+		if strings.ToLower(k) == key {
+			return v
+		}
+	}
+	if response, ok := gplResponses[key]; ok {
+		return response
+	}
+	return fmt.Sprintf("*Unknown command*: %s", command)
+}
@@ -0,0 +1,264 @@
+package Simulator
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SimConfig는 가상 장비 하나의 설정 (Address에서 TCP로 접속을 받음)
+type SimConfig struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`    // "loadport", "aligner", "robot", "gpl"
+	Address string `json:"address"` // 예: "127.0.0.1:5001"
+	DelayMs int    `json:"delayMs"` // 동작(MOV) 완료 또는 GPL 응답까지 걸리는 시간
+	// Fault는 동작 명령에 줄 고장 ("" 정상, "nak" 거부, "abs" 동작 실패, "noack" 무응답, "noinf" 완료 보고 없음)
+	Fault     string `json:"fault"`
+	AlarmCode string `json:"alarmCode"` // 비어 있지 않으면 알람 상태 (상태에 반영, 동작 명령 거부, RSET/INIT로 해제)
+	SlotMap   string `json:"slotMap"`   // 로드포트 매핑 결과 (비어 있으면 25슬롯 모두 웨이퍼 있음)
+	// GPL 전용: 로그인 비밀번호 (비어 있으면 "Help")와 명령별 응답 (기본 응답 위에 덮어씀)
+	Password  string            `json:"password"`
+	Responses map[string]string `json:"responses"`
+}
+
+// simHandler는 장비 종류별 동작, 접속 하나마다 serve가 호출됨
+type simHandler interface {
+	serve(conn *simConn)
+}
+
+type simServer struct {
+	cfg      SimConfig
+	listener net.Listener
+	handler  simHandler
+	conns    map[*simConn]bool
+	closed   bool
+	mu       sync.Mutex
+}
+
+var (
+	servers   = make(map[string]*simServer)
+	serversMu sync.Mutex
+)
+
+// Kinds는 지원하는 가상 장비 종류
+func Kinds() []string {
+	return []string{"loadport", "aligner", "robot", "gpl"}
+}
+
+// Start는 가상 장비를 시작 (같은 이름이 실행 중이면 멈추고 다시 시작)
+func Start(cfg SimConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("가상 장비 이름이 없습니다")
+	}
+	Stop(cfg.Name)
+
+	srv := &simServer{cfg: cfg, conns: make(map[*simConn]bool)}
+	switch cfg.Kind {
+	case "loadport":
+		srv.handler = newLoadportSim(srv)
+	case "aligner":
+		srv.handler = newAlignerSim(srv)
+	case "robot":
+		srv.handler = newRobotSim(srv)
+	case "gpl":
+		srv.handler = &gplSim{srv: srv}
+	default:
+		return fmt.Errorf("지원하지 않는 가상 장비 종류입니다: %s", cfg.Kind)
+	}
+
+	listener, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		return fmt.Errorf("%s 가상 장비 시작 실패: %v", cfg.Name, err)
+	}
+	srv.listener = listener
+
+	serversMu.Lock()
+	servers[cfg.Name] = srv
+	serversMu.Unlock()
+
+	fmt.Printf("[%s] 가상 %s 장비 시작: %s\n", cfg.Name, cfg.Kind, listener.Addr())
+	go srv.acceptLoop()
+	return nil
+}
+
+// Stop은 가상 장비를 멈추고 접속을 모두 끊음
+func Stop(name string) error {
+	serversMu.Lock()
+	srv, ok := servers[name]
+	delete(servers, name)
+	serversMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	srv.mu.Lock()
+	srv.closed = true
+	conns := srv.conns
+	srv.conns = make(map[*simConn]bool)
+	srv.mu.Unlock()
+
+	err := srv.listener.Close()
+	for c := range conns {
+		c.conn.Close()
+	}
+	fmt.Printf("[%s] 가상 장비 종료\n", name)
+	return err
+}
+
+// StopAll은 실행 중인 가상 장비를 모두 멈춤
+func StopAll() {
+	for _, cfg := range List() {
+		Stop(cfg.Name)
+	}
+}
+
+// Update는 실행 중인 가상 장비의 지연, 고장, 알람, 매핑 결과, 응답을 바꿈 (주소와 종류는 그대로)
+func Update(cfg SimConfig) error {
+	serversMu.Lock()
+	srv, ok := servers[cfg.Name]
+	serversMu.Unlock()
+	if !ok {
+		return fmt.Errorf("%s 가상 장비가 실행 중이 아닙니다", cfg.Name)
+	}
+
+	srv.mu.Lock()
+	cfg.Kind, cfg.Address = srv.cfg.Kind, srv.cfg.Address
+	srv.cfg = cfg
+	srv.mu.Unlock()
+	return nil
+}
+
+// List는 실행 중인 가상 장비 설정 (Address는 실제 리슨 주소)
+func List() []SimConfig {
+	serversMu.Lock()
+	defer serversMu.Unlock()
+	list := make([]SimConfig, 0, len(servers))
+	for _, srv := range servers {
+		cfg := srv.config()
+		cfg.Address = srv.listener.Addr().String()
+		list = append(list, cfg)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func (s *simServer) config() SimConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg
+}
+
+// delay는 설정된 동작 시간
+func (s *simServer) delay() time.Duration {
+	return time.Duration(s.config().DelayMs) * time.Millisecond
+}
+
+func (s *simServer) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &simConn{conn: conn, reader: bufio.NewReader(conn)}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[c] = true
+		s.mu.Unlock()
+
+		go func() {
+			s.handler.serve(c)
+			conn.Close()
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// simConn은 접속 하나, 완료 보고 등 여러 고루틴에서 쓰므로 쓰기를 직렬화
+type simConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	mu     sync.Mutex
+}
+
+func (c *simConn) write(data string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.conn.Write([]byte(data))
+	return err
+}
+
+// Telnet 명령 바이트 (클라이언트 협상을 읽고 버리는 데만 사용)
+const (
+	telnetSE   = 0xF0
+	telnetSB   = 0xFA
+	telnetWILL = 0xFB
+	telnetIAC  = 0xFF
+)
+
+// readLine은 CR/LF/NUL로 끝나는 한 줄을 읽음 (Telnet IAC 협상은 무시)
+func (c *simConn) readLine() (string, error) {
+	const (
+		stData = iota
+		stIAC
+		stOption
+		stSB
+		stSBIAC
+	)
+	state := stData
+	var line []byte
+	for {
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		switch state {
+		case stIAC:
+			switch {
+			case b == telnetIAC: // IAC IAC == 데이터 0xFF
+				line = append(line, b)
+				state = stData
+			case b == telnetSB:
+				state = stSB
+			case b >= telnetWILL:
+				state = stOption
+			default:
+				state = stData
+			}
+			continue
+		case stOption:
+			state = stData
+			continue
+		case stSB:
+			if b == telnetIAC {
+				state = stSBIAC
+			}
+			continue
+		case stSBIAC:
+			state = stSB
+			if b == telnetSE {
+				state = stData
+			}
+			continue
+		}
+
+		switch b {
+		case telnetIAC:
+			state = stIAC
+		case '\r', '\n', 0:
+			if len(line) > 0 {
+				return string(line), nil
+			}
+		default:
+			line = append(line, b)
+		}
+	}
+}
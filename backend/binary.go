@@ -0,0 +1,45 @@
+package backend
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RawHandler는 바이너리 모드 세션의 원본 송수신 바이트를 받음 (dataType == "RECV" 또는 "SENT")
+type RawHandler func(sessionID, dataType string, data []byte)
+
+// 공백으로 구분한 두 자리 16진수 나열 ("02 41 30 03")
+var hexPayloadPattern = regexp.MustCompile(`^[0-9A-Fa-f]{2}(\s+[0-9A-Fa-f]{2})*$`)
+
+// ParsePayload는 바이너리 모드 입력을 바이트로 변환
+// "02 41 30 03" 처럼 16진수 나열이면 16진수로, 아니면 이스케이프 문자열("\x02A0\x03", "\r")로 해석
+func ParsePayload(s string) ([]byte, error) {
+	trimmed := strings.TrimSpace(s)
+	if hexPayloadPattern.MatchString(trimmed) {
+		payload, err := hex.DecodeString(strings.Join(strings.Fields(trimmed), ""))
+		if err != nil {
+			return nil, fmt.Errorf("잘못된 16진수 입력입니다: %q", s)
+		}
+		return payload, nil
+	}
+	return unescapeBytes(s)
+}
+
+// HexDump는 바이트를 16진수와 ASCII로 나란히 표시 ("02 41 30 03 | .A0.")
+func HexDump(data []byte) string {
+	var hexPart, asciiPart strings.Builder
+	for i, c := range data {
+		if i > 0 {
+			hexPart.WriteByte(' ')
+		}
+		fmt.Fprintf(&hexPart, "%02X", c)
+		if c >= 0x20 && c < 0x7F {
+			asciiPart.WriteByte(c)
+		} else {
+			asciiPart.WriteByte('.')
+		}
+	}
+	return hexPart.String() + " | " + asciiPart.String()
+}
@@ -0,0 +1,150 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 기본 응답 대기 시간
+const defaultExpectTimeout = 5 * time.Second
+
+// ExpectResult는 SendAndExpect가 받은 응답
+type ExpectResult struct {
+	Command  string   `json:"command"`  // 전송한 명령
+	Response string   `json:"response"` // 전송 후 패턴이 일치할 때까지 받은 메시지 (줄바꿈으로 연결)
+	Lines    []string `json:"lines"`    // 받은 메시지 목록
+	Groups   []string `json:"groups"`   // 정규식 전체 일치와 캡처 그룹
+}
+
+// expectWaiter는 응답을 기다리는 요청 하나
+type expectWaiter struct {
+	command string
+	pattern *regexp.Regexp
+	lines   []string
+	result  chan ExpectResult
+}
+
+// SendAndExpect는 data를 전송하고 expectPattern(정규식)과 일치하는 응답이 올 때까지 기다림
+// 패턴이 비어 있으면 첫 수신 메시지를 응답으로 사용, timeout <= 0 이면 5초
+// 같은 세션의 요청은 순서대로 하나씩 처리되어 응답이 명령과 짝지어짐
+func SendAndExpect(sessionID, data, expectPattern string, timeout time.Duration) (ExpectResult, error) {
+	return getSessionManager().sendAndExpect(context.Background(), sessionID, data, expectPattern, timeout)
+}
+
+// SendAndExpectContext는 ctx가 취소되면 바로 대기를 끝내는 SendAndExpect (사이클 테스트 EMO/Stop)
+func SendAndExpectContext(ctx context.Context, sessionID, data, expectPattern string, timeout time.Duration) (ExpectResult, error) {
+	return getSessionManager().sendAndExpect(ctx, sessionID, data, expectPattern, timeout)
+}
+
+func (m *sessionManager) sendAndExpect(ctx context.Context, sessionID, data, expectPattern string, timeout time.Duration) (ExpectResult, error) {
+	pattern, err := regexp.Compile(expectPattern)
+	if err != nil {
+		return ExpectResult{}, fmt.Errorf("응답 패턴 정규식 오류: %v", err)
+	}
+	if timeout <= 0 {
+		timeout = defaultExpectTimeout
+	}
+	session, err := m.get(sessionID)
+	if err != nil {
+		return ExpectResult{}, err
+	}
+
+	session.expectMu.Lock()
+	defer session.expectMu.Unlock()
+
+	waiter := &expectWaiter{command: data, pattern: pattern, result: make(chan ExpectResult, 1)}
+	session.waiterMu.Lock()
+	session.waiter = waiter
+	session.waiterMu.Unlock()
+	defer func() {
+		session.waiterMu.Lock()
+		if session.waiter == waiter {
+			session.waiter = nil
+		}
+		session.waiterMu.Unlock()
+	}()
+
+	if err = m.send(sessionID, data); err != nil {
+		return ExpectResult{Command: data}, err
+	}
+
+	select {
+	case result := <-waiter.result:
+		return result, nil
+	case <-time.After(timeout):
+		session.waiterMu.Lock()
+		lines := append([]string(nil), waiter.lines...)
+		session.waiterMu.Unlock()
+		return ExpectResult{Command: data, Response: strings.Join(lines, "\n"), Lines: lines},
+			fmt.Errorf("%s 응답 대기 시간 초과 (%v): %s", sessionID, timeout, data)
+	case <-session.closed:
+		return ExpectResult{Command: data}, fmt.Errorf("%s 응답 대기 중 세션이 종료되었습니다: %s", sessionID, data)
+	case <-ctx.Done():
+		return ExpectResult{Command: data}, ctx.Err()
+	}
+}
+
+// ListenSession은 세션의 수신 메시지를 fn으로 받음 (스크립트 expect용), 반환된 함수로 해제
+func ListenSession(sessionID string, fn func(message string)) (func(), error) {
+	session, err := getSessionManager().get(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.waiterMu.Lock()
+	if session.listeners == nil {
+		session.listeners = make(map[int]func(string))
+	}
+	session.nextListener++
+	key := session.nextListener
+	session.listeners[key] = fn
+	session.waiterMu.Unlock()
+
+	return func() {
+		session.waiterMu.Lock()
+		delete(session.listeners, key)
+		session.waiterMu.Unlock()
+	}, nil
+}
+
+// SessionClosed는 세션이 어떤 경로로든 닫히면 닫히는 채널을 반환 (사용자 종료, CLOSE, GAVEUP)
+func SessionClosed(sessionID string) (<-chan struct{}, error) {
+	session, err := getSessionManager().get(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return session.closed, nil
+}
+
+// deliver는 수신 메시지를 구독자와 응답 대기 중인 요청에 넘김
+// 구독자는 잠금 밖에서 호출 (구독자 안에서 ListenSession이나 해제 함수를 불러도 멈추지 않도록)
+func (s *Session) deliver(message string) {
+	s.waiterMu.Lock()
+	listeners := make([]func(string), 0, len(s.listeners))
+	for _, fn := range s.listeners {
+		listeners = append(listeners, fn)
+	}
+	s.waiterMu.Unlock()
+	for _, fn := range listeners {
+		fn(message)
+	}
+
+	s.waiterMu.Lock()
+	defer s.waiterMu.Unlock()
+	waiter := s.waiter
+	if waiter == nil {
+		return
+	}
+
+	waiter.lines = append(waiter.lines, message)
+	response := strings.Join(waiter.lines, "\n")
+	groups := waiter.pattern.FindStringSubmatch(response)
+	if groups == nil {
+		return
+	}
+	s.waiter = nil
+	waiter.result <- ExpectResult{Command: waiter.command, Response: response, Lines: waiter.lines, Groups: groups}
+}
@@ -0,0 +1,272 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FramingConfig는 수신 데이터를 메시지 단위로 자르는 규칙
+type FramingConfig struct {
+	Mode         string `json:"mode"`         // "delimiter", "fixed", "length", "idle", "none"
+	Delimiter    string `json:"delimiter"`    // delimiter 모드 구분자 ("CRLF", "ETX", "\r\n", "\x03" ...)
+	Length       int    `json:"length"`       // fixed 모드 프레임 길이
+	LengthSize   int    `json:"lengthSize"`   // length 모드 길이 필드 크기 (1, 2, 4)
+	LittleEndian bool   `json:"littleEndian"` // length 모드 길이 필드 바이트 순서
+	IdleGapMs    int    `json:"idleGapMs"`    // idle 모드 프레임 종료로 간주할 무수신 시간
+	// FlushTimeoutMs는 delimiter/fixed/length 모드에서 미완성 프레임을 그대로 내보내기까지의 무수신 시간 (0 == 사용 안 함)
+	FlushTimeoutMs int `json:"flushTimeoutMs"`
+}
+
+// 최대 버퍼 크기, 이 이상 프레임이 완성되지 않으면 그대로 내보냄
+const maxFrameBuffer = 64 * 1024
+
+var terminatorPresets = map[string]string{
+	"CRLF": "\r\n",
+	"CR":   "\r",
+	"LF":   "\n",
+	"ETX":  "\x03",
+	"NONE": "",
+}
+
+// ParseTerminator는 프리셋 이름("CRLF", "CR", "LF", "ETX", "NONE") 또는 이스케이프 문자열("\r\n", "\x03")을 바이트로 변환
+// 빈 문자열은 기본값 CRLF
+func ParseTerminator(s string) ([]byte, error) {
+	if s == "" {
+		return []byte("\r\n"), nil
+	}
+	if preset, ok := terminatorPresets[strings.ToUpper(s)]; ok {
+		return []byte(preset), nil
+	}
+	return unescapeBytes(s)
+}
+
+// unescapeBytes는 \r, \n, \t, \\, \xHH 이스케이프를 해석
+func unescapeBytes(s string) ([]byte, error) {
+	var out []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			out = append(out, s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return nil, fmt.Errorf("잘못된 이스케이프 문자열입니다: %q", s)
+		}
+		i++
+		switch s[i] {
+		case 'r':
+			out = append(out, '\r')
+		case 'n':
+			out = append(out, '\n')
+		case 't':
+			out = append(out, '\t')
+		case '0':
+			out = append(out, 0)
+		case '\\':
+			out = append(out, '\\')
+		case 'x':
+			if i+2 >= len(s) {
+				return nil, fmt.Errorf("잘못된 이스케이프 문자열입니다: %q", s)
+			}
+			v, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("잘못된 이스케이프 문자열입니다: %q", s)
+			}
+			out = append(out, byte(v))
+			i += 2
+		default:
+			return nil, fmt.Errorf("지원하지 않는 이스케이프 문자입니다: \\%c", s[i])
+		}
+	}
+	return out, nil
+}
+
+// framer는 수신 조각을 모아 완성된 프레임을 돌려줌
+type framer interface {
+	// Feed는 수신 데이터를 추가하고 완성된 프레임 목록을 반환
+	Feed(data []byte) [][]byte
+	// Flush는 남아 있는 미완성 데이터를 프레임으로 꺼냄
+	Flush() []byte
+}
+
+// framingDefaulter는 설정이 비어 있을 때 쓰는 전송 방식별 기본 프레이밍을 제공
+type framingDefaulter interface {
+	DefaultFraming() FramingConfig
+}
+
+// messageTransport는 메시지 단위로 보내는 전송 방식 (종료 문자 기본값 없음)
+type messageTransport interface {
+	MessageOriented() bool
+}
+
+// newFramer는 설정으로 framer와 flush 대기 시간(0 == 사용 안 함)을 생성
+func newFramer(cfg FramingConfig) (framer, time.Duration, error) {
+	if cfg.FlushTimeoutMs < 0 {
+		return nil, 0, fmt.Errorf("잘못된 FlushTimeoutMs 입니다: %d", cfg.FlushTimeoutMs)
+	}
+	flushTimeout := time.Duration(cfg.FlushTimeoutMs) * time.Millisecond
+
+	switch strings.ToLower(cfg.Mode) {
+	case "", "delimiter":
+		delim, err := ParseTerminator(cfg.Delimiter)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(delim) == 0 {
+			return nil, 0, fmt.Errorf("delimiter 모드에는 구분자가 필요합니다")
+		}
+		return &delimiterFramer{delimiter: delim}, flushTimeout, nil
+	case "fixed":
+		if cfg.Length <= 0 || cfg.Length > maxFrameBuffer {
+			return nil, 0, fmt.Errorf("잘못된 고정 길이입니다: %d", cfg.Length)
+		}
+		return &fixedFramer{length: cfg.Length}, flushTimeout, nil
+	case "length":
+		switch cfg.LengthSize {
+		case 1, 2, 4:
+		default:
+			return nil, 0, fmt.Errorf("길이 필드 크기는 1, 2, 4 중 하나여야 합니다: %d", cfg.LengthSize)
+		}
+		return &lengthFramer{size: cfg.LengthSize, littleEndian: cfg.LittleEndian}, flushTimeout, nil
+	case "idle":
+		if cfg.IdleGapMs <= 0 {
+			return nil, 0, fmt.Errorf("idle 모드에는 IdleGapMs가 필요합니다")
+		}
+		return &rawFramer{}, time.Duration(cfg.IdleGapMs) * time.Millisecond, nil
+	case "none":
+		return &rawFramer{passThrough: true}, 0, nil
+	default:
+		return nil, 0, fmt.Errorf("지원하지 않는 프레이밍 모드입니다: %s", cfg.Mode)
+	}
+}
+
+// delimiterFramer는 구분자 단위로 자름 (구분자는 제외)
+type delimiterFramer struct {
+	delimiter []byte
+	buffer    bytes.Buffer
+}
+
+func (f *delimiterFramer) Feed(data []byte) [][]byte {
+	f.buffer.Write(data)
+	var frames [][]byte
+	for {
+		index := bytes.Index(f.buffer.Bytes(), f.delimiter)
+		if index == -1 {
+			break
+		}
+		frame := make([]byte, index)
+		copy(frame, f.buffer.Next(index))
+		f.buffer.Next(len(f.delimiter))
+		frames = append(frames, frame)
+	}
+	if f.buffer.Len() > maxFrameBuffer {
+		frames = append(frames, f.Flush())
+	}
+	return frames
+}
+
+func (f *delimiterFramer) Flush() []byte {
+	return takeBuffer(&f.buffer)
+}
+
+// fixedFramer는 고정 길이 단위로 자름
+type fixedFramer struct {
+	length int
+	buffer bytes.Buffer
+}
+
+func (f *fixedFramer) Feed(data []byte) [][]byte {
+	f.buffer.Write(data)
+	var frames [][]byte
+	for f.buffer.Len() >= f.length {
+		frame := make([]byte, f.length)
+		copy(frame, f.buffer.Next(f.length))
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+func (f *fixedFramer) Flush() []byte {
+	return takeBuffer(&f.buffer)
+}
+
+// lengthFramer는 앞쪽 길이 필드가 가리키는 만큼 읽어 본문만 반환
+type lengthFramer struct {
+	size         int
+	littleEndian bool
+	buffer       bytes.Buffer
+}
+
+func (f *lengthFramer) Feed(data []byte) [][]byte {
+	f.buffer.Write(data)
+	var frames [][]byte
+	for f.buffer.Len() >= f.size {
+		header := f.buffer.Bytes()[:f.size]
+		var order binary.ByteOrder = binary.BigEndian
+		if f.littleEndian {
+			order = binary.LittleEndian
+		}
+		var length int
+		switch f.size {
+		case 1:
+			length = int(header[0])
+		case 2:
+			length = int(order.Uint16(header))
+		case 4:
+			length = int(order.Uint32(header))
+		}
+		if length > maxFrameBuffer {
+			// 길이 필드가 깨진 경우 버퍼를 비우고 다시 동기화
+			frames = append(frames, f.Flush())
+			break
+		}
+		if f.buffer.Len() < f.size+length {
+			break
+		}
+		f.buffer.Next(f.size)
+		frame := make([]byte, length)
+		copy(frame, f.buffer.Next(length))
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+func (f *lengthFramer) Flush() []byte {
+	return takeBuffer(&f.buffer)
+}
+
+// rawFramer는 모아두었다가 Flush 때 내보내거나(idle), 받은 그대로 전달(none)
+type rawFramer struct {
+	passThrough bool
+	buffer      bytes.Buffer
+}
+
+func (f *rawFramer) Feed(data []byte) [][]byte {
+	if f.passThrough {
+		frame := make([]byte, len(data))
+		copy(frame, data)
+		return [][]byte{frame}
+	}
+	f.buffer.Write(data)
+	if f.buffer.Len() > maxFrameBuffer {
+		return [][]byte{f.Flush()}
+	}
+	return nil
+}
+
+func (f *rawFramer) Flush() []byte {
+	return takeBuffer(&f.buffer)
+}
+
+func takeBuffer(buffer *bytes.Buffer) []byte {
+	if buffer.Len() == 0 {
+		return nil
+	}
+	frame := make([]byte, buffer.Len())
+	copy(frame, buffer.Bytes())
+	buffer.Reset()
+	return frame
+}
@@ -0,0 +1,587 @@
+package backend
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	RegisterTransport("hsms", newHSMSTransport)
+}
+
+// HSMS 세션 종류 (SType, PType은 SECS-II == 0만 지원)
+const (
+	hsmsData        byte = 0
+	hsmsSelectReq   byte = 1
+	hsmsSelectRsp   byte = 2
+	hsmsDeselectReq byte = 3
+	hsmsDeselectRsp byte = 4
+	hsmsLinktestReq byte = 5
+	hsmsLinktestRsp byte = 6
+	hsmsRejectReq   byte = 7
+	hsmsSeparateReq byte = 9
+)
+
+const hsmsMaxLength = 16 << 20 // 수신 메시지 길이 상한 (메모리 보호)
+
+var hsmsControlNames = map[byte]string{
+	hsmsSelectReq:   "Select.req",
+	hsmsSelectRsp:   "Select.rsp",
+	hsmsDeselectReq: "Deselect.req",
+	hsmsDeselectRsp: "Deselect.rsp",
+	hsmsLinktestReq: "Linktest.req",
+	hsmsLinktestRsp: "Linktest.rsp",
+	hsmsRejectReq:   "Reject.req",
+	hsmsSeparateReq: "Separate.req",
+}
+
+// HSMSConfig는 HSMS(SEMI E37) 설정, 시간은 ms (0이면 표준 기본값)
+type HSMSConfig struct {
+	Mode       string `json:"mode"`       // "active"(기본, 접속) 또는 "passive"(리슨)
+	SessionID  int    `json:"sessionId"`  // 데이터 메시지의 세션(장치) ID
+	T3Ms       int    `json:"t3Ms"`       // 응답 메시지 (45초)
+	T5Ms       int    `json:"t5Ms"`       // 연결 시도 간격 (10초)
+	T6Ms       int    `json:"t6Ms"`       // 제어 트랜잭션 (5초)
+	T7Ms       int    `json:"t7Ms"`       // 접속 후 Select 대기 (10초)
+	T8Ms       int    `json:"t8Ms"`       // 메시지 내 문자 간 (5초)
+	LinktestMs int    `json:"linktestMs"` // 주기적 Linktest 간격 (0이면 보내지 않음)
+}
+
+func (c HSMSConfig) normalize() HSMSConfig {
+	if c.Mode == "" {
+		c.Mode = "active"
+	}
+	defaults := []struct {
+		v   *int
+		def int
+	}{{&c.T3Ms, 45000}, {&c.T5Ms, 10000}, {&c.T6Ms, 5000}, {&c.T7Ms, 10000}, {&c.T8Ms, 5000}}
+	for _, d := range defaults {
+		if *d.v <= 0 {
+			*d.v = d.def
+		}
+	}
+	return c
+}
+
+var (
+	errHSMST8          = errors.New("T8 시간 초과 (메시지 수신 중단)")
+	errHSMSNotSelected = errors.New("선택(Select)되지 않은 상태입니다")
+)
+
+// hsmsConnectTimes는 주소별 마지막 접속 시도(예약) 시각 (재연결마다 전송이 새로 만들어지므로 T5를 전송 밖에서 기억)
+var (
+	hsmsConnectMu    sync.Mutex
+	hsmsConnectTimes = make(map[string]time.Time)
+)
+
+// hsmsFrame은 수신한 메시지 하나 (10바이트 헤더 + 본문)
+type hsmsFrame struct {
+	header SECSHeader
+	pType  byte
+	sType  byte
+	body   []byte
+}
+
+// hsmsTransport는 HSMS-SS 연결 하나
+// Active는 접속 후 Select.req를 보내고, Passive는 한 번에 한 접속만 받아 Select.req를 기다림
+// Passive는 연결이 끊겨도 세션을 유지한 채 다음 접속을 기다림
+type hsmsTransport struct {
+	addr   string
+	cfg    HSMSConfig
+	events TransportEvents
+	tx     *secsTransactions
+
+	listener net.Listener
+	conn     net.Conn
+	selected bool
+	closing  bool
+	done     chan struct{}             // Close 시 닫힘 (T5 대기 취소)
+	pending  map[uint32]chan hsmsFrame // 응답을 기다리는 제어 메시지 (시스템 바이트)
+	mu       sync.Mutex
+	writeMu  sync.Mutex
+}
+
+func newHSMSTransport(cfg SessionConfig) (Transport, error) {
+	hc := cfg.HSMS.normalize()
+	if hc.Mode != "active" && hc.Mode != "passive" {
+		return nil, fmt.Errorf("HSMS 모드는 active 또는 passive여야 합니다: %s", hc.Mode)
+	}
+	if hc.SessionID < 0 || hc.SessionID > 0x7FFF {
+		return nil, fmt.Errorf("잘못된 세션 ID입니다: %d", hc.SessionID)
+	}
+	return &hsmsTransport{addr: cfg.Address, cfg: hc, done: make(chan struct{}), pending: make(map[uint32]chan hsmsFrame)}, nil
+}
+
+func (t *hsmsTransport) Open(events TransportEvents) error {
+	t.events = events
+	t.tx = newSECSTransactions(ms(t.cfg.T3Ms), func(h SECSHeader) {
+		t.info("T3 시간 초과 (응답 없음): %s", h)
+	})
+	if t.cfg.Mode == "passive" {
+		return t.listen()
+	}
+	return t.connect()
+}
+
+// connect는 Select.req로 선택 상태를 만듦, 같은 주소의 이전 접속 시도 후 T5가 지나지 않았으면 남은 시간만큼 기다림
+func (t *hsmsTransport) connect() error {
+	if wait := t.reserveConnect(); wait > 0 {
+		t.info("T5 연결 간격 대기 (%v)", wait.Round(100*time.Millisecond))
+		select {
+		case <-t.done:
+			return fmt.Errorf("%s T5 대기 중 연결이 취소되었습니다", t.addr)
+		case <-time.After(wait):
+		}
+	}
+
+	conn, err := net.DialTimeout("tcp", t.addr, 3*time.Second)
+	if err != nil {
+		return fmt.Errorf("%s HSMS 연결 실패: %v", t.addr, err)
+	}
+	t.mu.Lock()
+	t.conn = conn
+	t.mu.Unlock()
+	go t.readLoop(conn)
+
+	rsp, err := t.control(conn, hsmsSelectReq, 0)
+	if err == nil && rsp.header.Function != 0 {
+		err = fmt.Errorf("Select 거부 (상태 %d)", rsp.header.Function)
+	}
+	if err != nil {
+		t.Close()
+		return fmt.Errorf("%s HSMS Select 실패: %v", t.addr, err)
+	}
+	t.setSelected(conn, true)
+	fmt.Printf("%s HSMS 연결 및 선택 완료 (세션 ID %d)\n", t.addr, t.cfg.SessionID)
+	return nil
+}
+
+// reserveConnect는 이 주소의 다음 접속 시각을 예약하고 그때까지 남은 시간을 반환
+func (t *hsmsTransport) reserveConnect() time.Duration {
+	hsmsConnectMu.Lock()
+	defer hsmsConnectMu.Unlock()
+	next := time.Now()
+	if earliest := hsmsConnectTimes[t.addr].Add(ms(t.cfg.T5Ms)); earliest.After(next) {
+		next = earliest
+	}
+	hsmsConnectTimes[t.addr] = next
+	return time.Until(next)
+}
+
+func (t *hsmsTransport) listen() error {
+	listener, err := net.Listen("tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("%s 리슨 실패: %v", t.addr, err)
+	}
+	t.mu.Lock()
+	t.listener = listener
+	t.mu.Unlock()
+	fmt.Printf("%s 에서 HSMS 접속 대기를 시작합니다.\n", t.addr)
+	go t.acceptLoop(listener)
+	return nil
+}
+
+func (t *hsmsTransport) acceptLoop(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			t.mu.Lock()
+			isClosing := t.closing
+			t.mu.Unlock()
+			if isClosing || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			t.Close()
+			t.events.OnClosed(fmt.Errorf("%s 접속 대기 실패: %v", t.addr, err))
+			return
+		}
+
+		t.mu.Lock()
+		busy := t.conn != nil || t.closing
+		if !busy {
+			t.conn = conn
+		}
+		t.mu.Unlock()
+		if busy {
+			t.info("이미 연결되어 있어 %s 의 접속을 거부합니다", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
+
+		t.info("%s 접속, Select.req 대기", conn.RemoteAddr())
+		time.AfterFunc(ms(t.cfg.T7Ms), func() {
+			t.mu.Lock()
+			expired := t.conn == conn && !t.selected
+			t.mu.Unlock()
+			if expired {
+				t.info("T7 시간 초과 (Select 없음), 연결을 끊습니다")
+				conn.Close()
+			}
+		})
+		go t.readLoop(conn)
+	}
+}
+
+// DefaultFraming은 메시지 하나가 한 번의 수신 (길이 단위 조립은 전송 계층에서 처리)
+func (t *hsmsTransport) DefaultFraming() FramingConfig {
+	return FramingConfig{Mode: "none"}
+}
+
+// MessageOriented는 세션이 종료 문자를 붙이지 않도록 함
+func (t *hsmsTransport) MessageOriented() bool {
+	return true
+}
+
+func (t *hsmsTransport) Close() error {
+	t.mu.Lock()
+	if t.closing {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closing = true
+	close(t.done)
+	listener, conn := t.listener, t.conn
+	t.mu.Unlock()
+
+	if conn != nil {
+		t.mu.Lock()
+		selected := t.selected
+		t.mu.Unlock()
+		if selected {
+			t.writeFrame(conn, t.controlHeader(t.tx.nextSystem()), hsmsSeparateReq, nil) // 선택 상태면 Separate로 알리고 끊음
+		}
+		conn.Close()
+	}
+	if listener != nil {
+		listener.Close()
+	}
+	if t.tx != nil {
+		t.tx.stop()
+	}
+	fmt.Printf("%s HSMS 연결이 해제되었습니다.\n", t.addr)
+	return nil
+}
+
+// Write는 "S1F1 W <본문>" 데이터 메시지 또는 제어 명령(SELECT, DESELECT, LINKTEST, SEPARATE)을 보냄
+func (t *hsmsTransport) Write(data []byte) error {
+	t.mu.Lock()
+	conn, selected := t.conn, t.selected
+	t.mu.Unlock()
+	if conn == nil {
+		return fmt.Errorf("%s 는 연결되어 있지 않습니다", t.addr)
+	}
+
+	text := strings.ToUpper(strings.TrimSpace(string(data)))
+	switch text {
+	case "SELECT", "DESELECT", "LINKTEST":
+		return t.userControl(conn, text)
+	case "SEPARATE":
+		t.writeFrame(conn, t.controlHeader(t.tx.nextSystem()), hsmsSeparateReq, nil)
+		t.info("송신 Separate.req")
+		conn.Close()
+		return nil
+	}
+
+	msg, err := ParseSECSMessage(string(data))
+	if err != nil {
+		return err
+	}
+	if !selected {
+		return fmt.Errorf("%s HSMS %v", t.addr, errHSMSNotSelected)
+	}
+	msg.Header.DeviceID = uint16(t.cfg.SessionID)
+	t.tx.assign(&msg.Header)
+	if err := t.writeFrame(conn, msg.Header, hsmsData, msg.Body); err != nil {
+		return err
+	}
+	t.info("송신 %s", msg.Header)
+	t.tx.sent(msg.Header)
+	return nil
+}
+
+// userControl은 Commander에서 입력한 제어 명령을 보내고 T6 안에 응답을 확인
+func (t *hsmsTransport) userControl(conn net.Conn, command string) error {
+	sType := map[string]byte{"SELECT": hsmsSelectReq, "DESELECT": hsmsDeselectReq, "LINKTEST": hsmsLinktestReq}[command]
+	rsp, err := t.control(conn, sType, 0)
+	if err != nil {
+		return fmt.Errorf("%s HSMS %s 실패: %v", t.addr, hsmsControlNames[sType], err)
+	}
+	switch {
+	case sType == hsmsSelectReq && rsp.header.Function == 0:
+		t.setSelected(conn, true)
+	case sType == hsmsDeselectReq && rsp.header.Function == 0:
+		t.setSelected(conn, false)
+	case sType != hsmsLinktestReq:
+		return fmt.Errorf("%s HSMS %s 거부 (상태 %d)", t.addr, hsmsControlNames[sType], rsp.header.Function)
+	}
+	return nil
+}
+
+// control은 제어 요청을 보내고 T6 동안 응답(같은 시스템 바이트)을 기다림
+func (t *hsmsTransport) control(conn net.Conn, sType byte, status byte) (hsmsFrame, error) {
+	header := t.controlHeader(t.tx.nextSystem())
+	header.Function = status
+	result := make(chan hsmsFrame, 1)
+	t.mu.Lock()
+	t.pending[header.System] = result
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, header.System)
+		t.mu.Unlock()
+	}()
+
+	if err := t.writeFrame(conn, header, sType, nil); err != nil {
+		return hsmsFrame{}, err
+	}
+	t.info("송신 %s", hsmsControlNames[sType])
+
+	timer := time.NewTimer(ms(t.cfg.T6Ms))
+	defer timer.Stop()
+	select {
+	case rsp := <-result:
+		if rsp.sType == hsmsRejectReq {
+			return rsp, fmt.Errorf("Reject.req 수신 (사유 %d)", rsp.header.Function)
+		}
+		return rsp, nil
+	case <-timer.C:
+		t.info("T6 시간 초과 (%s 응답 없음), 연결을 끊습니다", hsmsControlNames[sType])
+		conn.Close()
+		return hsmsFrame{}, errors.New("T6 시간 초과")
+	}
+}
+
+// controlHeader는 제어 메시지 헤더 (세션 ID는 0xFFFF)
+func (t *hsmsTransport) controlHeader(system uint32) SECSHeader {
+	return SECSHeader{DeviceID: 0xFFFF, System: system}
+}
+
+// writeFrame은 4바이트 길이 + 10바이트 헤더 + 본문을 한 번에 보냄
+func (t *hsmsTransport) writeFrame(conn net.Conn, h SECSHeader, sType byte, body []byte) error {
+	head := h.encode10(0, false)
+	if h.DeviceID == 0xFFFF {
+		head[0], head[1] = 0xFF, 0xFF
+	}
+	head[4], head[5] = 0, sType // PType 0 (SECS-II)
+	frame := binary.BigEndian.AppendUint32(nil, uint32(len(head)+len(body)))
+	frame = append(append(frame, head...), body...)
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write(frame); err != nil {
+		return fmt.Errorf("%s 데이터 전송 실패: %v", t.addr, err)
+	}
+	return nil
+}
+
+func (t *hsmsTransport) setSelected(conn net.Conn, selected bool) {
+	t.mu.Lock()
+	if t.conn != conn || t.selected == selected {
+		t.mu.Unlock()
+		return
+	}
+	t.selected = selected
+	t.mu.Unlock()
+
+	if selected {
+		t.info("선택됨 (SELECTED)")
+		if t.cfg.LinktestMs > 0 {
+			go t.linktestLoop(conn)
+		}
+	} else {
+		t.info("선택 해제됨 (NOT SELECTED)")
+	}
+}
+
+// linktestLoop는 선택 상태인 동안 주기적으로 Linktest를 보냄 (T6 초과 시 control이 연결을 끊음)
+func (t *hsmsTransport) linktestLoop(conn net.Conn) {
+	ticker := time.NewTicker(ms(t.cfg.LinktestMs))
+	defer ticker.Stop()
+	for range ticker.C {
+		t.mu.Lock()
+		active := t.conn == conn && t.selected
+		t.mu.Unlock()
+		if !active {
+			return
+		}
+		if _, err := t.control(conn, hsmsLinktestReq, 0); err != nil {
+			return
+		}
+	}
+}
+
+func (t *hsmsTransport) readLoop(conn net.Conn) {
+	var err error
+	for {
+		var frame hsmsFrame
+		if frame, err = t.readFrame(conn); err != nil {
+			break
+		}
+		t.handle(conn, frame)
+	}
+	conn.Close()
+
+	t.mu.Lock()
+	isClosing := t.closing
+	if t.conn == conn {
+		t.conn, t.selected = nil, false
+	}
+	t.mu.Unlock()
+	if isClosing {
+		return
+	}
+	t.tx.stop()
+
+	if err == errHSMST8 {
+		t.info("%v", err)
+	}
+	if t.cfg.Mode == "passive" {
+		t.info("연결 끊김, 다음 접속을 기다립니다")
+		return
+	}
+	t.Close()
+	if errors.Is(err, net.ErrClosed) || err == io.EOF {
+		t.events.OnClosed(nil)
+	} else {
+		t.events.OnClosed(fmt.Errorf("연결이 비정상적으로 종료되었습니다: %v", err))
+	}
+}
+
+// readFrame은 길이 첫 바이트는 무기한, 이후 바이트는 T8 안에 도착해야 함
+func (t *hsmsTransport) readFrame(conn net.Conn) (hsmsFrame, error) {
+	var frame hsmsFrame
+	lenBuf := make([]byte, 4)
+	conn.SetReadDeadline(time.Time{})
+	if _, err := io.ReadFull(conn, lenBuf[:1]); err != nil {
+		return frame, err
+	}
+	if err := t.readT8(conn, lenBuf[1:]); err != nil {
+		return frame, err
+	}
+	length := binary.BigEndian.Uint32(lenBuf)
+	if length < 10 || length > hsmsMaxLength {
+		return frame, fmt.Errorf("잘못된 메시지 길이입니다: %d", length)
+	}
+	data := make([]byte, length)
+	if err := t.readT8(conn, data); err != nil {
+		return frame, err
+	}
+
+	frame.header, _, _ = decode10(data[:10])
+	frame.header.Reply = false
+	frame.header.DeviceID = binary.BigEndian.Uint16(data[0:2])
+	frame.pType, frame.sType = data[4], data[5]
+	frame.body = data[10:]
+	return frame, nil
+}
+
+func (t *hsmsTransport) readT8(conn net.Conn, buf []byte) error {
+	for n := 0; n < len(buf); {
+		conn.SetReadDeadline(time.Now().Add(ms(t.cfg.T8Ms)))
+		k, err := conn.Read(buf[n:])
+		n += k
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return errHSMST8
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// handle은 수신 메시지 하나를 처리 (데이터는 세션으로, 제어는 응답 또는 대기 중인 요청에 전달)
+func (t *hsmsTransport) handle(conn net.Conn, f hsmsFrame) {
+	if f.pType != 0 {
+		t.info("지원하지 않는 PType %d, Reject", f.pType)
+		t.reject(conn, f, f.pType, 2)
+		return
+	}
+
+	switch f.sType {
+	case hsmsData:
+		t.mu.Lock()
+		selected := t.selected
+		t.mu.Unlock()
+		if !selected {
+			t.info("선택되지 않은 상태에서 데이터 수신 %s, Reject", f.header)
+			t.reject(conn, f, hsmsData, 4)
+			return
+		}
+		if f.header.DeviceID != uint16(t.cfg.SessionID) {
+			// HSMS-SS는 세션이 하나뿐이므로 다른 세션 ID는 선택되지 않은 엔티티로 보고 Reject
+			t.info("세션 ID가 다른 데이터 수신 %s (설정 %d), Reject", f.header, t.cfg.SessionID)
+			t.reject(conn, f, hsmsData, 4)
+			return
+		}
+		msg := SECSMessage{Header: f.header, Body: f.body}
+		t.tx.received(msg.Header)
+		t.events.OnReceive([]byte(msg.String()))
+	case hsmsSelectReq:
+		t.mu.Lock()
+		status := byte(0)
+		if t.selected {
+			status = 1 // 이미 선택됨
+		}
+		t.mu.Unlock()
+		t.info("수신 Select.req")
+		t.respond(conn, f, hsmsSelectRsp, status)
+		t.setSelected(conn, true)
+	case hsmsDeselectReq:
+		t.info("수신 Deselect.req")
+		t.respond(conn, f, hsmsDeselectRsp, 0)
+		t.setSelected(conn, false)
+	case hsmsLinktestReq:
+		t.respond(conn, f, hsmsLinktestRsp, 0)
+	case hsmsSeparateReq:
+		t.info("수신 Separate.req, 연결을 끊습니다")
+		t.setSelected(conn, false)
+		conn.Close()
+	case hsmsSelectRsp, hsmsDeselectRsp, hsmsLinktestRsp, hsmsRejectReq:
+		t.mu.Lock()
+		result, ok := t.pending[f.header.System]
+		t.mu.Unlock()
+		if ok {
+			select {
+			case result <- f:
+			default: // 같은 응답을 두 번 받음
+			}
+		} else if f.sType == hsmsRejectReq {
+			t.info("수신 Reject.req (사유 %d, 시스템 %08X)", f.header.Function, f.header.System)
+		} else {
+			t.info("요청하지 않은 %s 수신 (시스템 %08X)", hsmsControlNames[f.sType], f.header.System)
+		}
+	default:
+		t.info("지원하지 않는 SType %d, Reject", f.sType)
+		t.reject(conn, f, f.sType, 1)
+	}
+}
+
+// respond는 받은 제어 요청과 같은 시스템 바이트로 응답 (status는 헤더 3번째 바이트)
+func (t *hsmsTransport) respond(conn net.Conn, req hsmsFrame, sType byte, status byte) {
+	header := t.controlHeader(req.header.System)
+	header.Function = status
+	t.writeFrame(conn, header, sType, nil)
+}
+
+// reject는 Reject.req를 보냄 (2번째 바이트는 거부한 SType 또는 PType, 3번째는 사유)
+func (t *hsmsTransport) reject(conn net.Conn, req hsmsFrame, rejected byte, reason byte) {
+	header := t.controlHeader(req.header.System)
+	header.Stream, header.Function = rejected, reason
+	t.writeFrame(conn, header, hsmsRejectReq, nil)
+}
+
+func (t *hsmsTransport) info(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	fmt.Printf("[%s] HSMS %s\n", t.addr, message)
+	if t.events.OnInfo != nil {
+		t.events.OnInfo(message)
+	}
+}
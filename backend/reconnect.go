@@ -0,0 +1,187 @@
+package backend
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReconnectPolicy는 연결이 비정상 종료됐을 때의 자동 재연결 규칙
+type ReconnectPolicy struct {
+	Enabled        bool `json:"enabled"`
+	InitialDelayMs int  `json:"initialDelayMs"` // 첫 재시도 대기 (기본 1초), 실패할 때마다 두 배
+	MaxDelayMs     int  `json:"maxDelayMs"`     // 대기 상한 (기본 30초)
+	MaxAttempts    int  `json:"maxAttempts"`    // 최대 시도 횟수 (0 == 무제한)
+	// QueueSends가 true면 재연결 중 전송을 모아 두었다가 복구 후 순서대로 전송, false면 즉시 오류
+	QueueSends bool `json:"queueSends"`
+	MaxQueue   int  `json:"maxQueue"` // 대기열 최대 길이 (기본 100)
+}
+
+func (p ReconnectPolicy) delays() (initial, max time.Duration) {
+	initial, max = time.Second, 30*time.Second
+	if p.InitialDelayMs > 0 {
+		initial = time.Duration(p.InitialDelayMs) * time.Millisecond
+	}
+	if p.MaxDelayMs > 0 {
+		max = time.Duration(p.MaxDelayMs) * time.Millisecond
+	}
+	if max < initial {
+		max = initial
+	}
+	return initial, max
+}
+
+// startReconnect는 세션을 재연결 상태로 바꾸고 백그라운드에서 재시도
+func (m *sessionManager) startReconnect(session *Session) {
+	session.stateMu.Lock()
+	session.reconnecting = true
+	session.stateMu.Unlock()
+
+	go m.reconnectLoop(session)
+}
+
+func (m *sessionManager) reconnectLoop(session *Session) {
+	policy := session.Config.Reconnect
+	delay, maxDelay := policy.delays()
+
+	for attempt := 1; policy.MaxAttempts <= 0 || attempt <= policy.MaxAttempts; attempt++ {
+		session.emit("RECONNECTING", fmt.Sprintf("%s 재연결 시도 #%d (%v 후)", session.Config.Address, attempt, delay))
+
+		select {
+		case <-session.closed:
+			return // 대기 중 사용자가 세션을 닫음
+		case <-time.After(delay):
+		}
+
+		transport, err := newTransport(session.currentConfig())
+		var latch *closeLatch
+		if err == nil {
+			events := m.transportEvents(session, transport)
+			latch = &closeLatch{onClosed: events.OnClosed}
+			events.OnClosed = latch.handle
+			err = transport.Open(events)
+		}
+		if err == nil {
+			m.restore(session, transport, latch)
+			return
+		}
+		fmt.Printf("[%s] 재연결 실패 #%d: %v\n", session.ID, attempt, err)
+
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+
+	session.stateMu.Lock()
+	session.reconnecting = false
+	session.stateMu.Unlock()
+	session.rejectQueue("재연결에 실패하여 전송을 취소했습니다")
+	session.emit("GAVEUP", fmt.Sprintf("%s 재연결을 포기했습니다 (%d회 시도)", session.Config.Address, policy.MaxAttempts))
+	m.finish(session)
+}
+
+// closeLatch는 새 연결이 세션에 설치되기 전에 끊기면 OnClosed를 설치 후로 미룸
+// (설치 전에는 handleClosed가 이전 연결의 이벤트로 보고 무시함)
+type closeLatch struct {
+	mu        sync.Mutex
+	installed bool
+	closed    bool
+	err       error
+	onClosed  func(err error)
+}
+
+func (l *closeLatch) handle(err error) {
+	l.mu.Lock()
+	if !l.installed {
+		l.closed, l.err = true, err
+		l.mu.Unlock()
+		return
+	}
+	l.mu.Unlock()
+	l.onClosed(err)
+}
+
+// install은 설치 이후의 종료는 바로 전달하고, 그 전에 끊겼으면 지금 전달한 뒤 true를 반환
+func (l *closeLatch) install() bool {
+	l.mu.Lock()
+	l.installed = true
+	closed, err := l.closed, l.err
+	l.mu.Unlock()
+	if closed {
+		l.onClosed(err)
+	}
+	return closed
+}
+
+// restore는 새 연결로 교체하고 대기열에 쌓인 전송을 순서대로 보냄
+func (m *sessionManager) restore(session *Session, transport Transport, latch *closeLatch) {
+	select {
+	case <-session.closed:
+		// 연결하는 사이에 사용자가 세션을 닫음
+		transport.Close()
+		return
+	default:
+	}
+
+	session.rxMu.Lock()
+	session.framer.Flush() // 끊기기 전의 미완성 프레임은 버림
+	session.rxMu.Unlock()
+
+	session.stateMu.Lock()
+	session.transport = transport
+	session.stateMu.Unlock()
+	if latch.install() {
+		return // 교체 전에 다시 끊김, handleClosed가 재연결을 이어감 (대기열은 유지)
+	}
+
+	session.emit("RESTORED", fmt.Sprintf("%s 연결이 복구되었습니다", session.Config.Address))
+
+	// 재전송 중에 새로 쌓인 것까지 모두 비운 뒤 재연결 상태 해제
+	for {
+		session.stateMu.Lock()
+		queue := session.sendQueue
+		session.sendQueue = nil
+		if len(queue) == 0 {
+			session.reconnecting = false
+			session.stateMu.Unlock()
+			return
+		}
+		session.stateMu.Unlock()
+
+		for _, data := range queue {
+			if err := session.write(transport, data); err != nil {
+				session.emit("ERRO", fmt.Sprintf("대기 중이던 전송 실패 (%s): %v", data, err))
+			}
+		}
+	}
+}
+
+// enqueue는 재연결 중 전송을 대기열에 넣음 (stateMu를 잡은 상태에서 호출)
+func (s *Session) enqueue(data string) error {
+	policy := s.Config.Reconnect
+	if !policy.QueueSends {
+		return fmt.Errorf("%s 재연결 중이라 전송할 수 없습니다", s.ID)
+	}
+	maxQueue := policy.MaxQueue
+	if maxQueue <= 0 {
+		maxQueue = 100
+	}
+	if len(s.sendQueue) >= maxQueue {
+		return fmt.Errorf("%s 재연결 대기열이 가득 찼습니다 (%d)", s.ID, maxQueue)
+	}
+	s.sendQueue = append(s.sendQueue, data)
+	return nil
+}
+
+// rejectQueue는 대기열에 남은 전송을 모두 취소하고 알림
+func (s *Session) rejectQueue(reason string) {
+	s.stateMu.Lock()
+	queue := s.sendQueue
+	s.sendQueue = nil
+	s.stateMu.Unlock()
+
+	for _, data := range queue {
+		s.emit("ERRO", fmt.Sprintf("%s: %s", reason, data))
+	}
+}
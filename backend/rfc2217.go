@@ -0,0 +1,325 @@
+package backend
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+func init() {
+	RegisterTransport("rfc2217", newRFC2217Transport)
+}
+
+// COM-PORT-OPTION 명령 (RFC 2217, 서버 응답은 명령 + 100)
+const (
+	comSetBaudRate        byte = 1
+	comSetDataSize        byte = 2
+	comSetParity          byte = 3
+	comSetStopSize        byte = 4
+	comSetControl         byte = 5
+	comNotifyLineState    byte = 6
+	comNotifyModemState   byte = 7
+	comSetModemStateMask  byte = 11
+	comServerReplyOffset  byte = 100
+	comControlNoFlow      byte = 1
+	comControlHardwareRTS byte = 3
+	comControlDTROn       byte = 8
+	comControlDTROff      byte = 9
+	comControlRTSOn       byte = 11
+	comControlRTSOff      byte = 12
+)
+
+const (
+	rfc2217ReplyTimeout = 3 * time.Second
+	modemStateBits      = 0xF0 // CTS, DSR, RI, CD (하위 4비트는 변화 표시)
+)
+
+var rfc2217Parity = map[string]byte{"N": 1, "O": 2, "E": 3, "M": 4, "S": 5}
+var rfc2217StopSize = map[string]byte{"1": 1, "2": 2, "1.5": 3}
+
+// rfc2217Transport는 터미널 서버(Moxa, Lantronix 등)의 원격 시리얼 포트
+// Telnet COM-PORT-OPTION으로 회선 설정과 DTR/RTS를 바꾸고, 데이터는 BINARY 전송으로 그대로 주고받음
+type rfc2217Transport struct {
+	addr    string
+	line    SerialLineConfig
+	lines   *ModemLines // 연결 시 적용할 DTR/RTS
+	conn    net.Conn
+	proto   *telnetProtocol
+	events  TransportEvents
+	closing bool
+	mu      sync.Mutex
+
+	replyMu sync.Mutex
+	replies map[byte]chan []byte // 응답을 기다리는 명령 -> 응답 값
+	modem   byte                 // 마지막으로 알림받은 모뎀 상태
+}
+
+// RemoteSerialConnect는 터미널 서버(host:port)의 원격 포트를 로컬 SerialConnect와 같은 회선 설정으로 엶
+func RemoteSerialConnect(address string, line SerialLineConfig, onEvent EventHandler) (string, error) {
+	return OpenSession(SessionConfig{Type: "rfc2217", Address: address, Serial: line}, onEvent)
+}
+
+func newRFC2217Transport(cfg SessionConfig) (Transport, error) {
+	if err := cfg.Serial.validate(false); err != nil {
+		return nil, fmt.Errorf("%s 회선 설정 오류: %v", cfg.Address, err)
+	}
+	return &rfc2217Transport{addr: cfg.Address, line: cfg.Serial.normalize(), lines: cfg.ModemLines, replies: make(map[byte]chan []byte)}, nil
+}
+
+func (t *rfc2217Transport) Open(events TransportEvents) error {
+	conn, err := net.DialTimeout("tcp", t.addr, 3*time.Second)
+	if err != nil {
+		return fmt.Errorf("%s RFC 2217 연결 실패: %v", t.addr, err)
+	}
+	proto := newTelnetProtocol(conn, "", 0, 0)
+	proto.options[telOptBinary] = &optionState{acceptUs: true, acceptHim: true}
+	proto.options[telOptComPort] = &optionState{acceptUs: true}
+	proto.onComPort = t.handleComPort
+
+	t.mu.Lock()
+	t.conn, t.proto, t.events, t.closing = conn, proto, events, false
+	t.mu.Unlock()
+	go t.startReading(conn, proto)
+
+	// 데이터는 양방향 BINARY, 회선 제어는 이쪽이 COM-PORT-OPTION을 켬
+	proto.enable(telOptBinary, true)
+	proto.enable(telOptBinary, false)
+	proto.enable(telOptSGA, true)
+	proto.enable(telOptSGA, false)
+	proto.enable(telOptComPort, true)
+	if err := t.waitComPort(proto); err != nil {
+		t.Close()
+		return fmt.Errorf("%s %v", t.addr, err)
+	}
+
+	err = t.SetLine(t.line)
+	if err == nil {
+		err = t.SetModemLines(t.lines.values()) // 처음엔 로컬 포트와 같이 DTR/RTS ON, 재연결이면 마지막 요청 상태
+	}
+	if err != nil {
+		t.Close()
+		return err
+	}
+	proto.sendSubnegotiation(telOptComPort, []byte{comSetModemStateMask, modemStateBits})
+	fmt.Printf("%s 원격 포트에 성공적으로 연결되었습니다. (%s)\n", t.addr, t.line)
+	return nil
+}
+
+// waitComPort는 서버가 COM-PORT-OPTION을 받아들일 때까지 기다림
+func (t *rfc2217Transport) waitComPort(proto *telnetProtocol) error {
+	deadline := time.Now().Add(rfc2217ReplyTimeout)
+	for !proto.enabled(telOptComPort, true) {
+		if time.Now().After(deadline) {
+			return errors.New("서버가 RFC 2217 (COM-PORT-OPTION)을 지원하지 않습니다")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return nil
+}
+
+// DefaultFraming은 로컬 시리얼과 같은 수신 규칙 (CRLF 단위)
+func (t *rfc2217Transport) DefaultFraming() FramingConfig {
+	return FramingConfig{Mode: "delimiter", Delimiter: "CRLF"}
+}
+
+// SetLine은 Baud Rate, Data/Parity/Stop, 흐름 제어를 서버에 설정하고 응답을 확인
+func (t *rfc2217Transport) SetLine(line SerialLineConfig) error {
+	if err := line.validate(false); err != nil {
+		return fmt.Errorf("%s 회선 설정 오류: %v", t.addr, err)
+	}
+	line = line.normalize()
+	control := comControlNoFlow
+	if line.FlowControl == "rtscts" {
+		control = comControlHardwareRTS
+	}
+
+	commands := []struct {
+		cmd   byte
+		value []byte
+		name  string
+	}{
+		{comSetBaudRate, binary.BigEndian.AppendUint32(nil, uint32(line.BaudRate)), "Baud Rate"},
+		{comSetDataSize, []byte{byte(line.DataBits)}, "Data Bits"},
+		{comSetParity, []byte{rfc2217Parity[line.Parity]}, "Parity"},
+		{comSetStopSize, []byte{rfc2217StopSize[line.StopBits]}, "Stop Bits"},
+		{comSetControl, []byte{control}, "흐름 제어"},
+	}
+	for _, c := range commands {
+		reply, err := t.request(c.cmd, c.value)
+		if err != nil {
+			return fmt.Errorf("%s %s 설정 실패: %v", t.addr, c.name, err)
+		}
+		if string(reply) != string(c.value) {
+			return fmt.Errorf("%s 서버가 %s 설정을 거부했습니다 (요청 % X, 적용 % X)", t.addr, c.name, c.value, reply)
+		}
+	}
+
+	t.mu.Lock()
+	t.line = line
+	t.mu.Unlock()
+	return nil
+}
+
+// SetModemLines는 DTR/RTS 출력을 바꾸고 응답을 확인
+// RTS/CTS 흐름 제어 중이면 RTS는 서버가 관리하므로 보내지 않음 (일부 서버는 RTS 명령을 받으면 흐름 제어를 끔)
+func (t *rfc2217Transport) SetModemLines(dtr, rts bool) error {
+	t.mu.Lock()
+	hardwareFlow := t.line.FlowControl == "rtscts"
+	t.mu.Unlock()
+
+	values := []byte{comControlDTROff}
+	if dtr {
+		values[0] = comControlDTROn
+	}
+	if !hardwareFlow {
+		if rts {
+			values = append(values, comControlRTSOn)
+		} else {
+			values = append(values, comControlRTSOff)
+		}
+	}
+	for _, v := range values {
+		reply, err := t.request(comSetControl, []byte{v})
+		if err != nil {
+			return fmt.Errorf("%s DTR/RTS 설정 실패: %v", t.addr, err)
+		}
+		if string(reply) != string([]byte{v}) {
+			return fmt.Errorf("%s 서버가 DTR/RTS 설정을 거부했습니다 (요청 %02X, 적용 % X)", t.addr, v, reply)
+		}
+	}
+	return nil
+}
+
+// request는 COM-PORT-OPTION 명령을 보내고 서버 응답 값을 기다림
+func (t *rfc2217Transport) request(cmd byte, value []byte) ([]byte, error) {
+	t.mu.Lock()
+	proto, closed := t.proto, t.closing
+	t.mu.Unlock()
+	if proto == nil || closed {
+		return nil, fmt.Errorf("연결되어 있지 않습니다")
+	}
+
+	reply := make(chan []byte, 1)
+	t.replyMu.Lock()
+	t.replies[cmd] = reply
+	t.replyMu.Unlock()
+	defer func() {
+		t.replyMu.Lock()
+		delete(t.replies, cmd)
+		t.replyMu.Unlock()
+	}()
+
+	proto.sendSubnegotiation(telOptComPort, append([]byte{cmd}, value...))
+	select {
+	case v := <-reply:
+		return v, nil
+	case <-time.After(rfc2217ReplyTimeout):
+		return nil, fmt.Errorf("응답 시간 초과")
+	}
+}
+
+// handleComPort는 서버 응답과 상태 알림을 처리 (data[0]은 명령 + 100)
+func (t *rfc2217Transport) handleComPort(data []byte) {
+	if len(data) < 1 || data[0] < comServerReplyOffset {
+		return
+	}
+	cmd, value := data[0]-comServerReplyOffset, append([]byte(nil), data[1:]...)
+
+	switch cmd {
+	case comNotifyModemState:
+		if len(value) == 1 && value[0]&modemStateBits != t.modem {
+			t.modem = value[0] & modemStateBits
+			t.info(fmt.Sprintf("모뎀 상태 CTS=%s DSR=%s RI=%s CD=%s",
+				onOff(value[0]&0x10 != 0), onOff(value[0]&0x20 != 0), onOff(value[0]&0x40 != 0), onOff(value[0]&0x80 != 0)))
+		}
+		return
+	case comNotifyLineState:
+		if len(value) == 1 && value[0]&0x1E != 0 {
+			t.info(fmt.Sprintf("회선 오류 (overrun=%t parity=%t framing=%t break=%t)",
+				value[0]&0x02 != 0, value[0]&0x04 != 0, value[0]&0x08 != 0, value[0]&0x10 != 0))
+		}
+		return
+	}
+
+	t.replyMu.Lock()
+	reply, ok := t.replies[cmd]
+	t.replyMu.Unlock()
+	if ok {
+		select {
+		case reply <- value:
+		default:
+		}
+	}
+}
+
+func (t *rfc2217Transport) info(message string) {
+	fmt.Printf("[%s] RFC 2217 %s\n", t.addr, message)
+	if t.events.OnInfo != nil {
+		t.events.OnInfo(message)
+	}
+}
+
+func (t *rfc2217Transport) Close() error {
+	t.mu.Lock()
+	conn := t.conn
+	if conn == nil || t.closing {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closing = true
+	t.mu.Unlock()
+
+	err := conn.Close()
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		return fmt.Errorf("%s 연결 해제 실패: %v", t.addr, err)
+	}
+	fmt.Printf("%s 원격 포트 연결이 해제되었습니다.\n", t.addr)
+	return nil
+}
+
+func (t *rfc2217Transport) Write(data []byte) error {
+	t.mu.Lock()
+	conn, proto, closed := t.conn, t.proto, t.closing
+	t.mu.Unlock()
+	if proto == nil || closed {
+		return fmt.Errorf("%s 는 연결되어 있지 않습니다", t.addr)
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(2 * time.Second))
+	if _, err := proto.Write(data); err != nil {
+		return fmt.Errorf("%s 데이터 전송 실패: %v", t.addr, err)
+	}
+	fmt.Printf("[%s] 데이터 전송: %q\n", t.addr, data)
+	return nil
+}
+
+func (t *rfc2217Transport) startReading(conn net.Conn, proto *telnetProtocol) {
+	buff := make([]byte, 4096)
+	for {
+		n, err := proto.Read(buff)
+		if n > 0 {
+			received := make([]byte, n)
+			copy(received, buff[:n])
+			t.events.OnReceive(received)
+		}
+		if err != nil {
+			t.mu.Lock()
+			isClosing := t.closing
+			t.mu.Unlock()
+			if isClosing {
+				return
+			}
+			t.Close()
+			if errors.Is(err, net.ErrClosed) || err == io.EOF {
+				t.events.OnClosed(nil)
+			} else {
+				t.events.OnClosed(fmt.Errorf("연결이 비정상적으로 종료되었습니다: %v", err))
+			}
+			return
+		}
+	}
+}
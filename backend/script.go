@@ -0,0 +1,614 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 스크립트 문법 (한 줄에 한 문장, #으로 시작하면 주석, ${name}은 변수 값으로 치환)
+//
+//	session <id>              대상 세션 변경
+//	send <text>               전송
+//	expect <regex> [ms]       정규식과 일치하는 수신까지 대기 (기본 5초, 실패 시 중단), ${0} ${1} ...에 캡처 저장
+//	wait <ms>                 대기
+//	set <name> <expr>         변수 설정 (a + b, a - b 등 사칙 연산 가능)
+//	loop [count] ... end      반복 (count 생략 시 정지할 때까지), ${i}는 0부터 시작하는 반복 횟수
+//	if <expr> ... [else ...] end
+//	log <text>                로그 출력
+//	assert <expr> [message]   조건이 거짓이면 실패로 중단
+//	break                     가장 안쪽 loop 탈출
+//
+// expr은 값 하나(비어 있지 않고 0/false가 아니면 참) 또는 "a op b" (==, !=, <, >, <=, >=, =~, +, -, *, /, %)
+
+// ScriptProgress는 스크립트 진행 상황 이벤트
+// State == {"START", "LINE", "LOG", "PAUSED", "RESUMED", "DONE", "FAILED", "STOPPED"}
+type ScriptProgress struct {
+	RunID   string `json:"runId"`
+	State   string `json:"state"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ScriptEventHandler는 스크립트 진행 이벤트를 상위(App)로 전달
+type ScriptEventHandler func(progress ScriptProgress)
+
+type scriptStmt struct {
+	line     int
+	op       string
+	args     []string
+	rest     string // 명령어 뒤 원문 (send, log용)
+	body     []*scriptStmt
+	elseBody []*scriptStmt
+}
+
+var errScriptBreak = fmt.Errorf("break")
+
+// maxScriptBuffer는 expect용 수신 버퍼에 보관하는 최대 메시지 수
+const maxScriptBuffer = 1000
+
+// parseScript는 스크립트를 문장 트리로 변환
+func parseScript(source string) ([]*scriptStmt, error) {
+	root := &scriptStmt{op: "root"}
+	stack := []*scriptStmt{root}
+	inElse := []bool{false}
+
+	for index, raw := range strings.Split(source, "\n") {
+		lineNo := index + 1
+		text := strings.TrimSpace(raw)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		op, rest, _ := strings.Cut(text, " ")
+		op = strings.ToLower(op)
+		rest = strings.TrimSpace(rest)
+		args, err := splitScriptArgs(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%d번째 줄: %v", lineNo, err)
+		}
+		stmt := &scriptStmt{line: lineNo, op: op, args: args, rest: rest}
+
+		top := stack[len(stack)-1]
+		appendStmt := func() {
+			if inElse[len(inElse)-1] {
+				top.elseBody = append(top.elseBody, stmt)
+			} else {
+				top.body = append(top.body, stmt)
+			}
+		}
+
+		switch op {
+		case "end":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("%d번째 줄: 짝이 맞지 않는 end 입니다", lineNo)
+			}
+			stack, inElse = stack[:len(stack)-1], inElse[:len(inElse)-1]
+		case "else":
+			if top.op != "if" || inElse[len(inElse)-1] {
+				return nil, fmt.Errorf("%d번째 줄: if 없이 else를 사용했습니다", lineNo)
+			}
+			inElse[len(inElse)-1] = true
+		case "loop", "if":
+			if op == "if" && len(args) == 0 {
+				return nil, fmt.Errorf("%d번째 줄: if에 조건이 없습니다", lineNo)
+			}
+			appendStmt()
+			stack, inElse = append(stack, stmt), append(inElse, false)
+		case "session", "wait", "expect", "assert":
+			if len(args) == 0 {
+				return nil, fmt.Errorf("%d번째 줄: %s에 인자가 없습니다", lineNo, op)
+			}
+			appendStmt()
+		case "set":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("%d번째 줄: set <name> <expr> 형식이어야 합니다", lineNo)
+			}
+			appendStmt()
+		case "send", "log", "break":
+			appendStmt()
+		default:
+			return nil, fmt.Errorf("%d번째 줄: 알 수 없는 명령입니다: %s", lineNo, op)
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("%d번째 줄의 %s가 end로 닫히지 않았습니다", stack[len(stack)-1].line, stack[len(stack)-1].op)
+	}
+	return root.body, nil
+}
+
+// splitScriptArgs는 공백으로 나누되 큰따옴표 안은 하나로 묶음 (\"만 이스케이프, 나머지 역슬래시는 그대로)
+func splitScriptArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inQuote, hasToken := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inQuote && c == '\\' && i+1 < len(s) && s[i+1] == '"':
+			current.WriteByte('"')
+			i++
+		case c == '"':
+			inQuote = !inQuote
+			hasToken = true
+		case !inQuote && (c == ' ' || c == '\t'):
+			if hasToken {
+				args = append(args, current.String())
+				current.Reset()
+				hasToken = false
+			}
+		default:
+			current.WriteByte(c)
+			hasToken = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("따옴표가 닫히지 않았습니다")
+	}
+	if hasToken {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// scriptRun은 실행 중인 스크립트 하나
+type scriptRun struct {
+	id         string
+	sessionID  string
+	vars       map[string]string
+	onProgress ScriptEventHandler
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	// 일시정지 (pauseMu로 보호, resume이 닫히면 재개)
+	paused  bool
+	resume  chan struct{}
+	pauseMu sync.Mutex
+
+	// expect용 수신 버퍼 (bufMu로 보호)
+	buffer   []string
+	notify   chan struct{}
+	unlisten func()
+	bufMu    sync.Mutex
+}
+
+var (
+	scriptManagerOnce sync.Once
+	managerScript     *scriptManager
+)
+
+type scriptManager struct {
+	runs   map[string]*scriptRun
+	nextID int
+	mu     sync.Mutex
+}
+
+func getScriptManager() *scriptManager {
+	scriptManagerOnce.Do(func() {
+		managerScript = &scriptManager{runs: make(map[string]*scriptRun)}
+	})
+	return managerScript
+}
+
+// --- 공개 함수 ---
+
+// ScriptRun은 스크립트를 검사한 뒤 sessionID 세션을 대상으로 백그라운드에서 실행하고 실행 ID를 반환
+func ScriptRun(source, sessionID string, onProgress ScriptEventHandler) (string, error) {
+	return getScriptManager().run(source, sessionID, onProgress)
+}
+
+func ScriptPause(runID string) error {
+	return getScriptManager().pause(runID, true)
+}
+
+func ScriptResume(runID string) error {
+	return getScriptManager().pause(runID, false)
+}
+
+func ScriptStop(runID string) error {
+	run, err := getScriptManager().get(runID)
+	if err != nil {
+		return err
+	}
+	run.cancel()
+	return nil
+}
+
+// --- 비공개 메소드 ---
+func (m *scriptManager) run(source, sessionID string, onProgress ScriptEventHandler) (string, error) {
+	stmts, err := parseScript(source)
+	if err != nil {
+		return "", fmt.Errorf("스크립트 오류: %v", err)
+	}
+
+	m.mu.Lock()
+	m.nextID++
+	id := fmt.Sprintf("script-%d", m.nextID)
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &scriptRun{id: id, vars: make(map[string]string), onProgress: onProgress,
+		ctx: ctx, cancel: cancel, notify: make(chan struct{}, 1)}
+	m.runs[id] = run
+	m.mu.Unlock()
+
+	if err = run.attach(sessionID); err != nil {
+		m.remove(id)
+		cancel()
+		return "", err
+	}
+
+	run.emit("START", 0, fmt.Sprintf("%s 스크립트 시작 (%s)", id, sessionID))
+	go func() {
+		defer m.remove(id)
+		defer cancel()
+		err := run.exec(stmts)
+		run.detach()
+		switch {
+		case err == nil || err == errScriptBreak:
+			run.emit("DONE", 0, "스크립트 완료")
+		case ctx.Err() != nil:
+			run.emit("STOPPED", 0, "스크립트 정지")
+		default:
+			run.emit("FAILED", 0, err.Error())
+		}
+	}()
+	return id, nil
+}
+
+func (m *scriptManager) get(runID string) (*scriptRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return nil, fmt.Errorf("%s 스크립트는 실행 중이 아닙니다", runID)
+	}
+	return run, nil
+}
+
+func (m *scriptManager) remove(runID string) {
+	m.mu.Lock()
+	delete(m.runs, runID)
+	m.mu.Unlock()
+}
+
+func (m *scriptManager) pause(runID string, pause bool) error {
+	run, err := m.get(runID)
+	if err != nil {
+		return err
+	}
+
+	run.pauseMu.Lock()
+	defer run.pauseMu.Unlock()
+	if run.paused == pause {
+		return nil
+	}
+	run.paused = pause
+	if pause {
+		run.resume = make(chan struct{})
+		run.emit("PAUSED", 0, "스크립트 일시정지")
+	} else {
+		close(run.resume)
+		run.emit("RESUMED", 0, "스크립트 재개")
+	}
+	return nil
+}
+
+func (r *scriptRun) emit(state string, line int, message string) {
+	if r.onProgress != nil {
+		r.onProgress(ScriptProgress{RunID: r.id, State: state, Line: line, Message: message})
+	}
+}
+
+// attach는 대상 세션을 바꾸고 수신 버퍼를 새로 시작
+func (r *scriptRun) attach(sessionID string) error {
+	unlisten, err := ListenSession(sessionID, func(message string) {
+		r.bufMu.Lock()
+		r.buffer = append(r.buffer, message)
+		if len(r.buffer) > maxScriptBuffer {
+			r.buffer = r.buffer[len(r.buffer)-maxScriptBuffer:] // 오래된 수신부터 버림
+		}
+		r.bufMu.Unlock()
+		select {
+		case r.notify <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	r.detach()
+	r.bufMu.Lock()
+	r.sessionID = sessionID
+	r.unlisten = unlisten
+	r.buffer = nil
+	r.bufMu.Unlock()
+	return nil
+}
+
+func (r *scriptRun) detach() {
+	r.bufMu.Lock()
+	unlisten := r.unlisten
+	r.unlisten = nil
+	r.bufMu.Unlock()
+	if unlisten != nil {
+		unlisten()
+	}
+}
+
+// checkpoint는 문장마다 정지/일시정지를 확인
+func (r *scriptRun) checkpoint() error {
+	r.pauseMu.Lock()
+	paused, resume := r.paused, r.resume
+	r.pauseMu.Unlock()
+	if paused {
+		select {
+		case <-resume:
+		case <-r.ctx.Done():
+		}
+	}
+	return r.ctx.Err()
+}
+
+func (r *scriptRun) exec(stmts []*scriptStmt) error {
+	for _, stmt := range stmts {
+		if err := r.checkpoint(); err != nil {
+			return err
+		}
+		if err := r.execStmt(stmt); err != nil {
+			if err == errScriptBreak || r.ctx.Err() != nil || stmt.op == "if" || stmt.op == "loop" {
+				return err // 블록 안의 오류는 이미 줄 번호가 붙어 있음
+			}
+			return fmt.Errorf("%d번째 줄: %v", stmt.line, err)
+		}
+	}
+	return nil
+}
+
+func (r *scriptRun) execStmt(stmt *scriptStmt) error {
+	args := make([]string, len(stmt.args))
+	for i, arg := range stmt.args {
+		args[i] = r.expand(arg)
+	}
+	if stmt.op != "loop" && stmt.op != "if" {
+		r.emit("LINE", stmt.line, r.expand(stmt.rest))
+	}
+
+	switch stmt.op {
+	case "session":
+		return r.attach(args[0])
+	case "send":
+		data := r.expand(stmt.rest)
+		if len(args) == 1 && strings.HasPrefix(stmt.rest, `"`) {
+			data = args[0]
+		}
+		r.bufMu.Lock()
+		r.buffer = nil // expect는 이 send 이후의 수신만 확인
+		r.bufMu.Unlock()
+		return SessionSend(r.sessionID, data)
+	case "expect":
+		timeout := defaultExpectTimeout
+		if len(args) > 1 {
+			ms, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("잘못된 대기 시간입니다: %s", args[1])
+			}
+			timeout = time.Duration(ms) * time.Millisecond
+		}
+		return r.expect(args[0], timeout)
+	case "wait":
+		ms, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("잘못된 대기 시간입니다: %s", args[0])
+		}
+		select {
+		case <-time.After(time.Duration(ms) * time.Millisecond):
+			return nil
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		}
+	case "set":
+		value, err := evalScriptExpr(args[1:])
+		if err != nil {
+			return err
+		}
+		r.vars[args[0]] = value
+		return nil
+	case "log":
+		message := r.expand(stmt.rest)
+		if len(args) == 1 && strings.HasPrefix(stmt.rest, `"`) {
+			message = args[0]
+		}
+		r.emit("LOG", stmt.line, message)
+		return nil
+	case "assert":
+		exprLen := len(args)
+		if exprLen == 2 || exprLen == 4 {
+			exprLen-- // 마지막 인자는 실패 메시지
+		}
+		ok, err := evalScriptCond(args[:exprLen])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if exprLen < len(args) {
+				return fmt.Errorf("assert 실패: %s", args[exprLen])
+			}
+			return fmt.Errorf("assert 실패: %s", strings.Join(args, " "))
+		}
+		return nil
+	case "if":
+		ok, err := evalScriptCond(args)
+		if err != nil {
+			return fmt.Errorf("%d번째 줄: %v", stmt.line, err)
+		}
+		if ok {
+			return r.exec(stmt.body)
+		}
+		return r.exec(stmt.elseBody)
+	case "loop":
+		count := -1
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("%d번째 줄: 잘못된 반복 횟수입니다: %s", stmt.line, args[0])
+			}
+			count = n
+		}
+		for i := 0; count < 0 || i < count; i++ {
+			r.vars["i"] = strconv.Itoa(i)
+			if err := r.exec(stmt.body); err != nil {
+				if err == errScriptBreak {
+					return nil
+				}
+				return err
+			}
+			if err := r.checkpoint(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "break":
+		return errScriptBreak
+	}
+	return nil
+}
+
+// expect는 마지막 send 이후 버퍼에 쌓인 수신부터 확인하고, 일치한 메시지까지 버퍼에서 제거
+func (r *scriptRun) expect(expr string, timeout time.Duration) error {
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("expect 정규식 오류: %v", err)
+	}
+	deadline := time.After(timeout)
+	for {
+		r.bufMu.Lock()
+		for i, message := range r.buffer {
+			if groups := pattern.FindStringSubmatch(message); groups != nil {
+				r.buffer = r.buffer[i+1:]
+				r.bufMu.Unlock()
+				for n, group := range groups {
+					r.vars[strconv.Itoa(n)] = group
+				}
+				return nil
+			}
+		}
+		r.bufMu.Unlock()
+
+		select {
+		case <-r.notify:
+		case <-deadline:
+			return fmt.Errorf("expect 시간 초과 (%v): %s", timeout, expr)
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		}
+	}
+}
+
+var scriptVarPattern = regexp.MustCompile(`\$\{(\w+)\}`)
+
+func (r *scriptRun) expand(s string) string {
+	return scriptVarPattern.ReplaceAllStringFunc(s, func(token string) string {
+		return r.vars[token[2:len(token)-1]]
+	})
+}
+
+// evalScriptExpr는 값 하나 또는 "a op b"를 계산해 문자열로 반환
+func evalScriptExpr(args []string) (string, error) {
+	switch len(args) {
+	case 1:
+		return args[0], nil
+	case 3:
+	default:
+		return "", fmt.Errorf("식은 값 하나 또는 \"a op b\" 형식이어야 합니다: %s", strings.Join(args, " "))
+	}
+
+	left, op, right := args[0], args[1], args[2]
+	a, errA := strconv.ParseFloat(left, 64)
+	b, errB := strconv.ParseFloat(right, 64)
+	numeric := errA == nil && errB == nil
+	boolString := func(v bool) string {
+		if v {
+			return "1"
+		}
+		return "0"
+	}
+	formatNumber := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	switch op {
+	case "==":
+		if numeric {
+			return boolString(a == b), nil
+		}
+		return boolString(left == right), nil
+	case "!=":
+		if numeric {
+			return boolString(a != b), nil
+		}
+		return boolString(left != right), nil
+	case "=~":
+		re, err := regexp.Compile(right)
+		if err != nil {
+			return "", fmt.Errorf("정규식 오류: %v", err)
+		}
+		return boolString(re.MatchString(left)), nil
+	case "<", ">", "<=", ">=":
+		if !numeric {
+			return "", fmt.Errorf("숫자가 아닌 값은 비교할 수 없습니다: %s %s %s", left, op, right)
+		}
+		switch op {
+		case "<":
+			return boolString(a < b), nil
+		case ">":
+			return boolString(a > b), nil
+		case "<=":
+			return boolString(a <= b), nil
+		default:
+			return boolString(a >= b), nil
+		}
+	case "+", "-", "*", "/", "%":
+		if !numeric {
+			if op == "+" {
+				return left + right, nil // 문자열 연결
+			}
+			return "", fmt.Errorf("숫자가 아닌 값은 계산할 수 없습니다: %s %s %s", left, op, right)
+		}
+		switch op {
+		case "+":
+			return formatNumber(a + b), nil
+		case "-":
+			return formatNumber(a - b), nil
+		case "*":
+			return formatNumber(a * b), nil
+		case "/":
+			if b == 0 {
+				return "", fmt.Errorf("0으로 나눌 수 없습니다")
+			}
+			return formatNumber(a / b), nil
+		default:
+			// 정수 나머지, |b| < 1 이면 정수 변환 후 0이 됨
+			if int64(b) == 0 {
+				return "", fmt.Errorf("0으로 나눌 수 없습니다")
+			}
+			return formatNumber(float64(int64(a) % int64(b))), nil
+		}
+	}
+	return "", fmt.Errorf("지원하지 않는 연산자입니다: %s", op)
+}
+
+func evalScriptCond(args []string) (bool, error) {
+	value, err := evalScriptExpr(args)
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(value) {
+	case "", "0", "false":
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,14 @@
+package backend
+
+import "testing"
+
+func TestEvalScriptExprModulo(t *testing.T) {
+	if got, err := evalScriptExpr([]string{"7", "%", "3"}); err != nil || got != "1" {
+		t.Fatalf("7 %% 3 = %q, %v", got, err)
+	}
+	for _, divisor := range []string{"0", "0.5", "-0.5"} {
+		if _, err := evalScriptExpr([]string{"5", "%", divisor}); err == nil {
+			t.Fatalf("5 %% %s: 오류가 발생해야 합니다", divisor)
+		}
+	}
+}
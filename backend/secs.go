@@ -0,0 +1,241 @@
+package backend
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SECSHeader는 SECS 메시지 헤더 (SECS-I 블록 헤더와 HSMS 데이터 메시지 헤더 공통 부분)
+type SECSHeader struct {
+	DeviceID uint16 `json:"deviceId"`
+	Reply    bool   `json:"reply"` // R 비트 (장비 -> 호스트)
+	Stream   byte   `json:"stream"`
+	Function byte   `json:"function"`
+	Wait     bool   `json:"wait"` // W 비트 (응답 요청)
+	System   uint32 `json:"system"`
+}
+
+// SECSMessage는 헤더와 SECS-II 본문 (인코딩된 바이트)
+type SECSMessage struct {
+	Header SECSHeader `json:"header"`
+	Body   []byte     `json:"body"`
+}
+
+// String은 로그용 표기 "S1F1 W dev=1 sys=00000001 | 01 02 ..."
+func (h SECSHeader) String() string {
+	s := fmt.Sprintf("S%dF%d", h.Stream, h.Function)
+	if h.Wait {
+		s += " W"
+	}
+	s += fmt.Sprintf(" dev=%d sys=%08X", h.DeviceID, h.System)
+	if h.Reply {
+		s += " R"
+	}
+	return s
+}
+
+// String은 헤더 뒤 줄에 본문을 SML로 표기 (SECS-II로 해석할 수 없으면 "헤더 | 16진수")
+func (m SECSMessage) String() string {
+	if len(m.Body) == 0 {
+		return m.Header.String()
+	}
+	if item, err := DecodeSECSItem(m.Body); err == nil {
+		return m.Header.String() + "\n" + item.SML() + "."
+	}
+	return fmt.Sprintf("%s | % X", m.Header.String(), m.Body)
+}
+
+// ParseSECSMessage는 전송 문자열 "S1F1 W <본문>"을 해석 (DeviceID와 System은 전송 계층이 채움)
+// 응답 메시지는 "S6F12 sys=0000002A <본문>"처럼 응답할 1차 메시지의 시스템 바이트를 지정할 수 있음
+// 본문이 '<'로 시작하면 SML, 아니면 16진수/이스케이프 원본 바이트
+func ParseSECSMessage(text string) (SECSMessage, error) {
+	var msg SECSMessage
+	text = strings.TrimSuffix(strings.TrimSpace(text), ".") // SML 메시지 끝 '.'
+	head, rest, _ := strings.Cut(text, " ")
+	stream, function, err := parseStreamFunction(head)
+	if err != nil {
+		return msg, err
+	}
+	msg.Header.Stream, msg.Header.Function = stream, function
+
+	rest = strings.TrimSpace(rest)
+	if rest == "W" || strings.HasPrefix(rest, "W ") {
+		msg.Header.Wait = true
+		rest = strings.TrimSpace(rest[1:])
+	}
+	if strings.HasPrefix(rest, "sys=") {
+		field, remain, _ := strings.Cut(rest, " ")
+		system, err := strconv.ParseUint(field[len("sys="):], 16, 32)
+		if err != nil {
+			return msg, fmt.Errorf("시스템 바이트 해석 실패: %v", err)
+		}
+		msg.Header.System = uint32(system)
+		rest = strings.TrimSpace(remain)
+	}
+	if strings.HasPrefix(rest, "<") {
+		item, err := ParseSML(rest)
+		if err != nil {
+			return msg, err
+		}
+		msg.Body, err = item.Encode()
+		return msg, err
+	}
+	if rest != "" {
+		if msg.Body, err = ParsePayload(rest); err != nil {
+			return msg, fmt.Errorf("SECS 본문 형식 오류: %v", err)
+		}
+	}
+	return msg, nil
+}
+
+// parseStreamFunction은 "S1F1"을 스트림과 함수 번호로 해석
+func parseStreamFunction(s string) (byte, byte, error) {
+	upper := strings.ToUpper(s)
+	sPart, fPart, ok := strings.Cut(strings.TrimPrefix(upper, "S"), "F")
+	if !ok || !strings.HasPrefix(upper, "S") {
+		return 0, 0, fmt.Errorf("SECS 메시지는 SxFy로 시작해야 합니다: %q", s)
+	}
+	stream, err1 := strconv.ParseUint(sPart, 10, 7)
+	function, err2 := strconv.ParseUint(fPart, 10, 8)
+	if err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("잘못된 스트림/함수 번호입니다: %q", s)
+	}
+	return byte(stream), byte(function), nil
+}
+
+// encode10은 10바이트 헤더 (SECS-I 블록 번호/E 비트 포함)
+func (h SECSHeader) encode10(block uint16, last bool) []byte {
+	b := make([]byte, 10)
+	binary.BigEndian.PutUint16(b[0:2], h.DeviceID&0x7FFF)
+	if h.Reply {
+		b[0] |= 0x80
+	}
+	b[2] = h.Stream & 0x7F
+	if h.Wait {
+		b[2] |= 0x80
+	}
+	b[3] = h.Function
+	binary.BigEndian.PutUint16(b[4:6], block&0x7FFF)
+	if last {
+		b[4] |= 0x80
+	}
+	binary.BigEndian.PutUint32(b[6:10], h.System)
+	return b
+}
+
+// decode10은 10바이트 헤더를 해석하고 블록 번호와 E 비트를 함께 반환
+func decode10(b []byte) (h SECSHeader, block uint16, last bool) {
+	h.Reply = b[0]&0x80 != 0
+	h.DeviceID = binary.BigEndian.Uint16(b[0:2]) & 0x7FFF
+	h.Wait = b[2]&0x80 != 0
+	h.Stream = b[2] & 0x7F
+	h.Function = b[3]
+	last = b[4]&0x80 != 0
+	block = binary.BigEndian.Uint16(b[4:6]) & 0x7FFF
+	h.System = binary.BigEndian.Uint32(b[6:10])
+	return h, block, last
+}
+
+// secsTransactions는 시스템 바이트 할당과 응답 대기(T3)를 관리 (SECS-I, HSMS 공통)
+type secsTransactions struct {
+	timeout   time.Duration
+	onTimeout func(h SECSHeader)
+
+	mu        sync.Mutex
+	systemNo  uint32
+	primaries map[uint16][]uint32    // 응답을 기다리는 수신 1차 메시지 (스트림<<8|응답 함수 -> 받은 순서대로의 시스템 바이트)
+	t3        map[uint32]*time.Timer // 응답을 기다리는 송신 1차 메시지
+}
+
+func newSECSTransactions(timeout time.Duration, onTimeout func(h SECSHeader)) *secsTransactions {
+	return &secsTransactions{
+		timeout:   timeout,
+		onTimeout: onTimeout,
+		primaries: make(map[uint16][]uint32),
+		t3:        make(map[uint32]*time.Timer),
+	}
+}
+
+// nextSystem은 새 시스템 바이트 (제어 메시지에도 사용)
+func (x *secsTransactions) nextSystem() uint32 {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.systemNo++
+	return x.systemNo
+}
+
+// assign은 보낼 메시지의 시스템 바이트를 채움
+// 받은 1차 메시지에 대한 응답이면 h.System이 가리키는 대기 항목, 없으면 가장 먼저 받은 항목의 시스템 바이트
+func (x *secsTransactions) assign(h *SECSHeader) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	key := uint16(h.Stream)<<8 | uint16(h.Function)
+	if pending := x.primaries[key]; len(pending) > 0 && h.Function%2 == 0 {
+		i := 0
+		for j, system := range pending {
+			if system == h.System {
+				i = j
+				break
+			}
+		}
+		h.System = pending[i]
+		pending = append(pending[:i], pending[i+1:]...)
+		if len(pending) == 0 {
+			delete(x.primaries, key)
+		} else {
+			x.primaries[key] = pending
+		}
+		return
+	}
+	x.systemNo++
+	h.System = x.systemNo
+}
+
+// sent는 W 비트가 있는 1차 메시지를 보낸 뒤 T3 타이머 시작
+func (x *secsTransactions) sent(h SECSHeader) {
+	if !h.Wait || h.Function%2 == 0 {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.t3[h.System] = time.AfterFunc(x.timeout, func() {
+		x.mu.Lock()
+		_, waiting := x.t3[h.System]
+		delete(x.t3, h.System)
+		x.mu.Unlock()
+		if waiting {
+			x.onTimeout(h)
+		}
+	})
+}
+
+// received는 받은 1차 메시지를 응답 대기에 올리거나, 받은 응답으로 T3 타이머를 멈춤
+func (x *secsTransactions) received(h SECSHeader) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if h.Function%2 == 1 && h.Wait {
+		key := uint16(h.Stream)<<8 | uint16(h.Function+1)
+		x.primaries[key] = append(x.primaries[key], h.System)
+	} else if timer, ok := x.t3[h.System]; ok && h.Function%2 == 0 {
+		timer.Stop()
+		delete(x.t3, h.System)
+	}
+}
+
+// stop은 대기 중인 T3 타이머를 모두 멈추고 응답하지 않은 수신 1차 메시지를 잊음 (연결 종료)
+// 다음 연결에서 이전 연결의 시스템 바이트로 응답하지 않도록 함
+func (x *secsTransactions) stop() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for system, timer := range x.t3 {
+		timer.Stop()
+		delete(x.t3, system)
+	}
+	for key := range x.primaries {
+		delete(x.primaries, key)
+	}
+}
@@ -0,0 +1,414 @@
+package backend
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+func init() {
+	RegisterTransport("secs1", newSECS1Transport)
+}
+
+// SECS-I 회선 제어 문자
+const (
+	secsENQ byte = 0x05
+	secsEOT byte = 0x04
+	secsACK byte = 0x06
+	secsNAK byte = 0x15
+)
+
+const secs1MaxData = 244 // 블록 하나의 최대 본문 길이 (길이 바이트 254 - 헤더 10)
+
+// SECS1Config는 SECS-I(SEMI E4) 링크 설정, 시간은 ms (0이면 표준 기본값)
+type SECS1Config struct {
+	Line       string `json:"line"`     // 하부 연결 "serial"(기본), "tcp" 또는 "rfc2217" (터미널 서버)
+	DeviceID   int    `json:"deviceId"` // 보내는 메시지의 장치 ID
+	Master     bool   `json:"master"`   // true면 장비 역할 (경합 시 우선, R 비트 1)
+	T1Ms       int    `json:"t1Ms"`     // 문자 간 (0.5초)
+	T2Ms       int    `json:"t2Ms"`     // 프로토콜 응답 (10초)
+	T3Ms       int    `json:"t3Ms"`     // 응답 메시지 (45초)
+	T4Ms       int    `json:"t4Ms"`     // 블록 간 (45초)
+	RetryLimit int    `json:"retryLimit"`
+}
+
+func (c SECS1Config) normalize() SECS1Config {
+	if c.Line == "" {
+		c.Line = "serial"
+	}
+	defaults := []struct {
+		v   *int
+		def int
+	}{{&c.T1Ms, 500}, {&c.T2Ms, 10000}, {&c.T3Ms, 45000}, {&c.T4Ms, 45000}, {&c.RetryLimit, 3}}
+	for _, d := range defaults {
+		if *d.v <= 0 {
+			*d.v = d.def
+		}
+	}
+	return c
+}
+
+func ms(n int) time.Duration {
+	return time.Duration(n) * time.Millisecond
+}
+
+var (
+	errSECS1Timeout = errors.New("시간 초과")
+	errSECS1Closed  = errors.New("연결이 닫혔습니다")
+)
+
+// secs1Transport는 시리얼(또는 TCP) 바이트 연결 위의 SECS-I 블록 전송
+// 회선 제어는 run 고루틴 하나가 맡고, 완성된 메시지는 헤더를 해석한 문자열로 전달
+type secs1Transport struct {
+	addr   string
+	cfg    SECS1Config
+	line   Transport
+	events TransportEvents
+
+	rx      chan []byte
+	rxBuf   []byte
+	sendQ   chan *secs1Request
+	closed  chan struct{}
+	closing *sync.Once
+
+	tx *secsTransactions
+
+	// run 고루틴 전용
+	partial   *SECSMessage // 수신 중인 다중 블록 메시지
+	nextBlock uint16
+	lastBlock []byte // 중복 블록 검사용 마지막 헤더
+	t4        *time.Timer
+}
+
+type secs1Request struct {
+	msg    SECSMessage
+	result chan error
+}
+
+func newSECS1Transport(cfg SessionConfig) (Transport, error) {
+	sc := cfg.SECS1.normalize()
+	if sc.Line != "serial" && sc.Line != "tcp" && sc.Line != "rfc2217" {
+		return nil, fmt.Errorf("SECS-I 하부 연결은 serial, tcp 또는 rfc2217 이어야 합니다: %s", sc.Line)
+	}
+	if sc.DeviceID < 0 || sc.DeviceID > 0x7FFF {
+		return nil, fmt.Errorf("잘못된 장치 ID입니다: %d", sc.DeviceID)
+	}
+	line, err := newTransport(SessionConfig{Type: sc.Line, Address: cfg.Address, Serial: cfg.Serial})
+	if err != nil {
+		return nil, err
+	}
+	return &secs1Transport{addr: cfg.Address, cfg: sc, line: line}, nil
+}
+
+func (t *secs1Transport) Open(events TransportEvents) error {
+	t.events = events
+	t.rx = make(chan []byte, 64)
+	t.rxBuf = nil
+	t.sendQ = make(chan *secs1Request)
+	t.closed = make(chan struct{})
+	t.closing = &sync.Once{}
+	t.tx = newSECSTransactions(ms(t.cfg.T3Ms), func(h SECSHeader) {
+		t.info("T3 시간 초과 (응답 없음): %s", h)
+	})
+
+	err := t.line.Open(TransportEvents{
+		OnReceive: func(data []byte) {
+			select {
+			case t.rx <- data:
+			case <-t.closed:
+			}
+		},
+		OnClosed: func(err error) {
+			t.shutdown()
+			events.OnClosed(err)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[%s] SECS-I 링크 시작 (장치 ID %d, %s)\n", t.addr, t.cfg.DeviceID, map[bool]string{true: "Master", false: "Slave"}[t.cfg.Master])
+	go t.run()
+	return nil
+}
+
+func (t *secs1Transport) Close() error {
+	t.shutdown()
+	return t.line.Close()
+}
+
+func (t *secs1Transport) shutdown() {
+	t.closing.Do(func() {
+		close(t.closed)
+		t.tx.stop()
+	})
+}
+
+// DefaultFraming은 메시지 하나가 한 번의 수신 (블록 조립은 전송 계층에서 처리)
+func (t *secs1Transport) DefaultFraming() FramingConfig {
+	return FramingConfig{Mode: "none"}
+}
+
+// MessageOriented는 세션이 종료 문자를 붙이지 않도록 함
+func (t *secs1Transport) MessageOriented() bool {
+	return true
+}
+
+// Write는 "S1F1 W <본문>"을 블록으로 나누어 보내고 모든 블록이 ACK될 때까지 대기
+func (t *secs1Transport) Write(data []byte) error {
+	msg, err := ParseSECSMessage(string(data))
+	if err != nil {
+		return err
+	}
+	req := &secs1Request{msg: msg, result: make(chan error, 1)}
+	select {
+	case t.sendQ <- req:
+	case <-t.closed:
+		return fmt.Errorf("%s SECS-I %v", t.addr, errSECS1Closed)
+	}
+	select {
+	case err = <-req.result:
+		return err
+	case <-t.closed:
+		return fmt.Errorf("%s SECS-I %v", t.addr, errSECS1Closed)
+	}
+}
+
+func (t *secs1Transport) info(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	fmt.Printf("[%s] SECS-I %s\n", t.addr, message)
+	if t.events.OnInfo != nil {
+		t.events.OnInfo(message)
+	}
+}
+
+// run은 회선이 비어 있을 때 수신(ENQ)을 우선 처리하고, 그다음 송신 요청을 처리
+func (t *secs1Transport) run() {
+	for {
+		if len(t.rxBuf) > 0 {
+			b := t.rxBuf[0]
+			t.rxBuf = t.rxBuf[1:]
+			if b == secsENQ {
+				t.receiveBlock()
+			}
+			continue
+		}
+
+		var t4 <-chan time.Time
+		if t.t4 != nil {
+			t4 = t.t4.C
+		}
+		select {
+		case chunk := <-t.rx:
+			t.rxBuf = chunk
+		case req := <-t.sendQ:
+			req.result <- t.sendMessage(req.msg)
+		case <-t4:
+			t.info("T4 시간 초과, 수신 중인 메시지를 버립니다: %s", t.partial.Header)
+			t.partial, t.t4 = nil, nil
+		case <-t.closed:
+			return
+		}
+	}
+}
+
+func (t *secs1Transport) readByte(timeout time.Duration) (byte, error) {
+	for len(t.rxBuf) == 0 {
+		timer := time.NewTimer(timeout)
+		select {
+		case chunk := <-t.rx:
+			t.rxBuf = chunk
+		case <-timer.C:
+			return 0, errSECS1Timeout
+		case <-t.closed:
+			timer.Stop()
+			return 0, errSECS1Closed
+		}
+		timer.Stop()
+	}
+	b := t.rxBuf[0]
+	t.rxBuf = t.rxBuf[1:]
+	return b, nil
+}
+
+// drain은 회선이 T1 동안 조용해질 때까지 수신을 버림 (NAK 전)
+func (t *secs1Transport) drain() {
+	t.rxBuf = nil
+	for {
+		if _, err := t.readByte(ms(t.cfg.T1Ms)); err != nil {
+			return
+		}
+		t.rxBuf = nil
+	}
+}
+
+// receiveBlock은 ENQ를 받은 뒤 EOT로 응답하고 블록 하나를 받음
+func (t *secs1Transport) receiveBlock() {
+	if err := t.line.Write([]byte{secsEOT}); err != nil {
+		return
+	}
+	length, err := t.readByte(ms(t.cfg.T2Ms))
+	if err != nil {
+		if err == errSECS1Timeout {
+			t.info("T2 시간 초과 (길이 바이트 대기)")
+			t.line.Write([]byte{secsNAK})
+		}
+		return
+	}
+
+	block := make([]byte, 0, int(length)+2)
+	for len(block) < int(length)+2 {
+		b, err := t.readByte(ms(t.cfg.T1Ms))
+		if err != nil {
+			if err == errSECS1Timeout {
+				t.info("T1 시간 초과 (%d/%d 바이트)", len(block), int(length)+2)
+				t.line.Write([]byte{secsNAK})
+			}
+			return
+		}
+		block = append(block, b)
+	}
+
+	if length < 10 || length > 254 || checksum(block[:length]) != binary.BigEndian.Uint16(block[length:]) {
+		t.info("블록 오류 (길이 %d 또는 체크섬 불일치), NAK", length)
+		t.drain()
+		t.line.Write([]byte{secsNAK})
+		return
+	}
+	t.line.Write([]byte{secsACK})
+	t.acceptBlock(block[:10], block[10:length])
+}
+
+// acceptBlock은 중복 블록을 버리고 다중 블록 메시지를 조립, 마지막 블록이면 전달
+func (t *secs1Transport) acceptBlock(head, data []byte) {
+	if t.lastBlock != nil && string(t.lastBlock) == string(head) {
+		t.info("중복 블록 무시")
+		return
+	}
+	t.lastBlock = append([]byte(nil), head...)
+
+	header, blockNo, last := decode10(head)
+	if t.partial != nil && t.partial.Header.System == header.System && blockNo == t.nextBlock {
+		t.partial.Body = append(t.partial.Body, data...)
+	} else {
+		if t.partial != nil {
+			t.info("블록 순서 오류, 수신 중인 메시지를 버립니다: %s", t.partial.Header)
+		}
+		t.partial = &SECSMessage{Header: header, Body: append([]byte(nil), data...)}
+	}
+	t.nextBlock = blockNo + 1
+	if t.t4 != nil {
+		t.t4.Stop()
+		t.t4 = nil
+	}
+	if !last {
+		t.t4 = time.NewTimer(ms(t.cfg.T4Ms))
+		return
+	}
+
+	msg := *t.partial
+	t.partial = nil
+	t.tx.received(msg.Header)
+	t.events.OnReceive([]byte(msg.String()))
+}
+
+// sendMessage는 헤더를 채우고 블록으로 나누어 전송, W 비트면 T3 타이머 시작
+func (t *secs1Transport) sendMessage(msg SECSMessage) error {
+	msg.Header.DeviceID = uint16(t.cfg.DeviceID)
+	msg.Header.Reply = t.cfg.Master
+	t.tx.assign(&msg.Header)
+
+	body := msg.Body
+	for blockNo := uint16(1); ; blockNo++ {
+		n := len(body)
+		if n > secs1MaxData {
+			n = secs1MaxData
+		}
+		last := n == len(body)
+		block := append(msg.Header.encode10(blockNo, last), body[:n]...)
+		if err := t.sendBlock(block); err != nil {
+			t.info("전송 실패: %s (블록 %d): %v", msg.Header, blockNo, err)
+			return fmt.Errorf("%s SECS-I 전송 실패 (블록 %d): %v", t.addr, blockNo, err)
+		}
+		body = body[n:]
+		if last {
+			break
+		}
+	}
+	t.info("송신 %s", msg.Header)
+	t.tx.sent(msg.Header)
+	return nil
+}
+
+// sendBlock은 ENQ/EOT 핸드셰이크 후 블록을 보내고 ACK를 확인, 실패하면 RetryLimit까지 다시 시도
+// 경합(ENQ 동시 전송) 시 Slave는 상대 블록을 먼저 받고, Master는 EOT를 계속 기다림
+func (t *secs1Transport) sendBlock(block []byte) error {
+	frame := append([]byte{byte(len(block))}, block...)
+	frame = binary.BigEndian.AppendUint16(frame, checksum(block))
+
+	for retry := 0; retry <= t.cfg.RetryLimit; {
+		if err := t.line.Write([]byte{secsENQ}); err != nil {
+			return err
+		}
+		got, yielded, err := t.waitEOT()
+		if err != nil {
+			return err
+		}
+		if yielded {
+			continue // 상대 블록을 받은 뒤 다시 ENQ부터 (재시도 횟수에 포함하지 않음)
+		}
+		if !got {
+			retry++
+			t.info("T2 시간 초과 (EOT 대기), 재시도 %d/%d", retry, t.cfg.RetryLimit)
+			continue
+		}
+
+		if err := t.line.Write(frame); err != nil {
+			return err
+		}
+		b, err := t.readByte(ms(t.cfg.T2Ms))
+		if err == errSECS1Closed {
+			return err
+		}
+		if err == nil && b == secsACK {
+			return nil
+		}
+		retry++
+		if err != nil {
+			t.info("T2 시간 초과 (ACK 대기), 재시도 %d/%d", retry, t.cfg.RetryLimit)
+		} else {
+			t.info("블록 거부 (0x%02X), 재시도 %d/%d", b, retry, t.cfg.RetryLimit)
+		}
+	}
+	return fmt.Errorf("재시도 횟수 초과")
+}
+
+// waitEOT는 ENQ를 보낸 뒤 T2 동안 EOT를 기다림
+// Slave가 상대 ENQ를 받으면 그 블록을 먼저 받고 yielded == true
+func (t *secs1Transport) waitEOT() (got, yielded bool, err error) {
+	deadline := time.Now().Add(ms(t.cfg.T2Ms))
+	for {
+		b, err := t.readByte(time.Until(deadline))
+		switch {
+		case err == errSECS1Timeout:
+			return false, false, nil
+		case err != nil:
+			return false, false, err
+		case b == secsEOT:
+			return true, false, nil
+		case b == secsENQ && !t.cfg.Master:
+			t.receiveBlock()
+			return false, true, nil
+		}
+	}
+}
+
+// checksum은 헤더와 본문 바이트의 합 (하위 16비트)
+func checksum(data []byte) uint16 {
+	var sum uint16
+	for _, b := range data {
+		sum += uint16(b)
+	}
+	return sum
+}
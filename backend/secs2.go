@@ -0,0 +1,478 @@
+package backend
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SECS-II(SEMI E5) 항목 형식 코드 (형식 바이트의 상위 6비트)
+var secs2Formats = map[string]byte{
+	"L": 0o00, "B": 0o10, "BOOLEAN": 0o11, "A": 0o20, "J": 0o21,
+	"I8": 0o30, "I1": 0o31, "I2": 0o32, "I4": 0o34,
+	"F8": 0o40, "F4": 0o44,
+	"U8": 0o50, "U1": 0o51, "U2": 0o52, "U4": 0o54,
+}
+
+var secs2FormatNames = func() map[byte]string {
+	names := make(map[byte]string, len(secs2Formats))
+	for name, code := range secs2Formats {
+		names[code] = name
+	}
+	return names
+}()
+
+// SECSItem은 SECS-II 항목 하나 (Format에 따라 한 필드만 사용)
+type SECSItem struct {
+	Format string     `json:"format"` // "L", "A", "J", "B", "BOOLEAN", "U1"~"U8", "I1"~"I8", "F4", "F8"
+	Items  []SECSItem `json:"items,omitempty"`
+	Text   string     `json:"text,omitempty"`   // A, J
+	Bytes  []byte     `json:"bytes,omitempty"`  // B
+	Bools  []bool     `json:"bools,omitempty"`  // BOOLEAN
+	Uints  []uint64   `json:"uints,omitempty"`  // U1~U8
+	Ints   []int64    `json:"ints,omitempty"`   // I1~I8
+	Floats []float64  `json:"floats,omitempty"` // F4, F8
+}
+
+// size는 숫자 형식의 원소 하나 크기 (U4 -> 4)
+func (i SECSItem) size() int {
+	n, _ := strconv.Atoi(i.Format[1:])
+	return n
+}
+
+// Encode는 항목을 SECS-II 바이트로 변환
+func (i SECSItem) Encode() ([]byte, error) {
+	code, ok := secs2Formats[i.Format]
+	if !ok {
+		return nil, fmt.Errorf("지원하지 않는 SECS-II 형식입니다: %s", i.Format)
+	}
+
+	var data []byte
+	length := 0
+	switch i.Format {
+	case "L":
+		for _, child := range i.Items {
+			b, err := child.Encode()
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, b...)
+		}
+		length = len(i.Items)
+	case "A", "J":
+		data = []byte(i.Text)
+	case "B":
+		data = i.Bytes
+	case "BOOLEAN":
+		for _, v := range i.Bools {
+			data = append(data, map[bool]byte{true: 1, false: 0}[v])
+		}
+	case "U1", "U2", "U4", "U8":
+		for _, v := range i.Uints {
+			if i.size() < 8 && v>>(8*i.size()) != 0 {
+				return nil, fmt.Errorf("%s 범위를 벗어난 값입니다: %d", i.Format, v)
+			}
+			data = appendUint(data, v, i.size())
+		}
+	case "I1", "I2", "I4", "I8":
+		for _, v := range i.Ints {
+			bits := 8 * i.size()
+			if bits < 64 && (v < -1<<(bits-1) || v >= 1<<(bits-1)) {
+				return nil, fmt.Errorf("%s 범위를 벗어난 값입니다: %d", i.Format, v)
+			}
+			data = appendUint(data, uint64(v), i.size())
+		}
+	case "F4":
+		for _, v := range i.Floats {
+			data = binary.BigEndian.AppendUint32(data, math.Float32bits(float32(v)))
+		}
+	case "F8":
+		for _, v := range i.Floats {
+			data = binary.BigEndian.AppendUint64(data, math.Float64bits(v))
+		}
+	}
+	if i.Format != "L" {
+		length = len(data)
+	}
+
+	var head []byte
+	switch {
+	case length <= 0xFF:
+		head = []byte{code<<2 | 1, byte(length)}
+	case length <= 0xFFFF:
+		head = []byte{code<<2 | 2, byte(length >> 8), byte(length)}
+	case length <= 0xFFFFFF:
+		head = []byte{code<<2 | 3, byte(length >> 16), byte(length >> 8), byte(length)}
+	default:
+		return nil, fmt.Errorf("%s 항목이 너무 깁니다: %d", i.Format, length)
+	}
+	return append(head, data...), nil
+}
+
+func appendUint(b []byte, v uint64, size int) []byte {
+	for shift := 8 * (size - 1); shift >= 0; shift -= 8 {
+		b = append(b, byte(v>>shift))
+	}
+	return b
+}
+
+// DecodeSECSItem은 본문 전체를 항목 하나로 해석 (남는 바이트가 있으면 오류)
+func DecodeSECSItem(data []byte) (SECSItem, error) {
+	item, n, err := decodeItem(data, 0)
+	if err != nil {
+		return item, err
+	}
+	if n != len(data) {
+		return item, fmt.Errorf("SECS-II 항목 뒤에 %d바이트가 남았습니다", len(data)-n)
+	}
+	return item, nil
+}
+
+// secs2MaxDepth는 리스트 중첩 한도 (수신 데이터로 재귀가 깊어지는 것을 막음)
+const secs2MaxDepth = 64
+
+// decodeItem은 항목 하나를 해석하고 사용한 바이트 수를 반환 (depth는 리스트 중첩 깊이)
+func decodeItem(data []byte, depth int) (SECSItem, int, error) {
+	var item SECSItem
+	if depth > secs2MaxDepth {
+		return item, 0, fmt.Errorf("SECS-II 리스트 중첩이 %d단계를 넘습니다", secs2MaxDepth)
+	}
+	if len(data) < 1 {
+		return item, 0, fmt.Errorf("SECS-II 형식 바이트가 없습니다")
+	}
+	name, ok := secs2FormatNames[data[0]>>2]
+	lenBytes := int(data[0] & 0x03)
+	if !ok {
+		return item, 0, fmt.Errorf("알 수 없는 SECS-II 형식 코드입니다: 0o%02o", data[0]>>2)
+	}
+	if lenBytes == 0 || len(data) < 1+lenBytes {
+		return item, 0, fmt.Errorf("%s 길이 바이트 오류", name)
+	}
+	length := 0
+	for _, b := range data[1 : 1+lenBytes] {
+		length = length<<8 | int(b)
+	}
+	pos := 1 + lenBytes
+	item.Format = name
+
+	if name == "L" {
+		// 항목 하나는 최소 2바이트이므로 남은 데이터로 담을 수 없는 개수는 미리 거부
+		if length > (len(data)-pos)/2 {
+			return item, 0, fmt.Errorf("L 항목 개수(%d)보다 데이터가 짧습니다", length)
+		}
+		item.Items = make([]SECSItem, 0, length)
+		for k := 0; k < length; k++ {
+			child, n, err := decodeItem(data[pos:], depth+1)
+			if err != nil {
+				return item, 0, err
+			}
+			item.Items = append(item.Items, child)
+			pos += n
+		}
+		return item, pos, nil
+	}
+
+	if len(data) < pos+length {
+		return item, 0, fmt.Errorf("%s 항목 길이(%d)보다 데이터가 짧습니다", name, length)
+	}
+	value := data[pos : pos+length]
+	pos += length
+
+	switch name {
+	case "A", "J":
+		item.Text = string(value)
+	case "B":
+		item.Bytes = append([]byte{}, value...)
+	case "BOOLEAN":
+		item.Bools = make([]bool, 0, len(value))
+		for _, b := range value {
+			item.Bools = append(item.Bools, b != 0)
+		}
+	default:
+		size := item.size()
+		if len(value)%size != 0 {
+			return item, 0, fmt.Errorf("%s 항목 길이(%d)가 %d의 배수가 아닙니다", name, len(value), size)
+		}
+		for k := 0; k < len(value); k += size {
+			var v uint64
+			for _, b := range value[k : k+size] {
+				v = v<<8 | uint64(b)
+			}
+			switch name[0] {
+			case 'U':
+				item.Uints = append(item.Uints, v)
+			case 'I':
+				shift := 64 - 8*size
+				item.Ints = append(item.Ints, int64(v<<shift)>>shift) // 부호 확장
+			case 'F':
+				if size == 4 {
+					item.Floats = append(item.Floats, float64(math.Float32frombits(uint32(v))))
+				} else {
+					item.Floats = append(item.Floats, math.Float64frombits(v))
+				}
+			}
+		}
+	}
+	return item, pos, nil
+}
+
+// SML은 항목을 SML 텍스트로 표기 (리스트는 한 단계마다 두 칸 들여쓰기)
+func (i SECSItem) SML() string {
+	var sb strings.Builder
+	i.writeSML(&sb, 0)
+	return sb.String()
+}
+
+func (i SECSItem) writeSML(sb *strings.Builder, depth int) {
+	indent := strings.Repeat("  ", depth)
+	sb.WriteString(indent)
+	switch i.Format {
+	case "L":
+		fmt.Fprintf(sb, "<L [%d]", len(i.Items))
+		for _, child := range i.Items {
+			sb.WriteString("\n")
+			child.writeSML(sb, depth+1)
+		}
+		if len(i.Items) > 0 {
+			sb.WriteString("\n" + indent)
+		}
+		sb.WriteString(">")
+		return
+	case "A", "J":
+		fmt.Fprintf(sb, "<%s %s>", i.Format, smlQuote(i.Text))
+		return
+	}
+
+	var values []string
+	switch i.Format {
+	case "B":
+		for _, b := range i.Bytes {
+			values = append(values, fmt.Sprintf("0x%02X", b))
+		}
+	case "BOOLEAN":
+		for _, v := range i.Bools {
+			values = append(values, map[bool]string{true: "T", false: "F"}[v])
+		}
+	case "U1", "U2", "U4", "U8":
+		for _, v := range i.Uints {
+			values = append(values, strconv.FormatUint(v, 10))
+		}
+	case "I1", "I2", "I4", "I8":
+		for _, v := range i.Ints {
+			values = append(values, strconv.FormatInt(v, 10))
+		}
+	case "F4", "F8":
+		bits := 64
+		if i.Format == "F4" {
+			bits = 32
+		}
+		for _, v := range i.Floats {
+			values = append(values, strconv.FormatFloat(v, 'g', -1, bits))
+		}
+	}
+	if len(values) == 0 {
+		fmt.Fprintf(sb, "<%s>", i.Format)
+		return
+	}
+	fmt.Fprintf(sb, "<%s %s>", i.Format, strings.Join(values, " "))
+}
+
+// smlQuote는 문자열을 따옴표로 감싸고, 출력할 수 없는 문자는 따옴표 밖에 0x.. 로 표기 ("AB" 0x0D)
+func smlQuote(text string) string {
+	if text == "" {
+		return `""`
+	}
+	var parts []string
+	var run strings.Builder
+	flush := func() {
+		if run.Len() > 0 {
+			parts = append(parts, `"`+run.String()+`"`)
+			run.Reset()
+		}
+	}
+	for _, b := range []byte(text) {
+		if b < 0x20 || b == '"' || b >= 0x7F {
+			flush()
+			parts = append(parts, fmt.Sprintf("0x%02X", b))
+			continue
+		}
+		run.WriteByte(b)
+	}
+	flush()
+	return strings.Join(parts, " ")
+}
+
+// ParseSML은 SML 텍스트 "<L [2] <A "ABC"> <U4 1 2>>"를 항목으로 해석 (끝의 '.'은 무시)
+func ParseSML(text string) (SECSItem, error) {
+	p := &smlParser{src: strings.TrimSuffix(strings.TrimSpace(text), ".")}
+	item, err := p.item()
+	if err != nil {
+		return item, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return item, fmt.Errorf("SML 뒤에 해석하지 못한 내용이 있습니다: %q", p.src[p.pos:])
+	}
+	return item, nil
+}
+
+type smlParser struct {
+	src string
+	pos int
+}
+
+func (p *smlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("SML 형식 오류 (%d번째 문자): %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *smlParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *smlParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+// token은 공백, '<', '>', '"', '[' 전까지의 단어
+func (p *smlParser) token() string {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n<>\"[", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *smlParser) item() (SECSItem, error) {
+	var item SECSItem
+	if p.skipSpace(); p.peek() != '<' {
+		return item, p.errorf("'<'가 필요합니다")
+	}
+	p.pos++
+	p.skipSpace()
+	item.Format = strings.ToUpper(p.token())
+	if item.Format == "BOOL" {
+		item.Format = "BOOLEAN"
+	}
+	if _, ok := secs2Formats[item.Format]; !ok {
+		return item, p.errorf("지원하지 않는 형식입니다: %q", item.Format)
+	}
+
+	count := -1
+	if p.skipSpace(); p.peek() == '[' {
+		end := strings.IndexByte(p.src[p.pos:], ']')
+		if end < 0 {
+			return item, p.errorf("']'가 없습니다")
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(p.src[p.pos+1 : p.pos+end]))
+		if err != nil {
+			return item, p.errorf("잘못된 개수입니다: %q", p.src[p.pos+1:p.pos+end])
+		}
+		count = n
+		p.pos += end + 1
+	}
+
+	var err error
+	switch item.Format {
+	case "L":
+		item.Items = []SECSItem{}
+		for p.skipSpace(); p.peek() == '<'; p.skipSpace() {
+			child, err := p.item()
+			if err != nil {
+				return item, err
+			}
+			item.Items = append(item.Items, child)
+		}
+		if count >= 0 && count != len(item.Items) {
+			return item, p.errorf("L [%d] 에 항목이 %d개 있습니다", count, len(item.Items))
+		}
+	case "A", "J":
+		item.Text, err = p.text()
+	default:
+		err = p.values(&item)
+	}
+	if err != nil {
+		return item, err
+	}
+
+	if p.skipSpace(); p.peek() != '>' {
+		return item, p.errorf("'>'가 필요합니다")
+	}
+	p.pos++
+	return item, nil
+}
+
+// text는 따옴표 문자열과 0x.. 문자 코드를 이어 붙임
+func (p *smlParser) text() (string, error) {
+	var sb strings.Builder
+	for p.skipSpace(); p.peek() != '>' && p.peek() != 0; p.skipSpace() {
+		if p.peek() == '"' {
+			end := strings.IndexByte(p.src[p.pos+1:], '"')
+			if end < 0 {
+				return "", p.errorf("닫는 따옴표가 없습니다")
+			}
+			sb.WriteString(p.src[p.pos+1 : p.pos+1+end])
+			p.pos += end + 2
+			continue
+		}
+		word := p.token()
+		v, err := strconv.ParseUint(word, 0, 8)
+		if err != nil {
+			return "", p.errorf("문자열은 따옴표나 0x.. 코드여야 합니다: %q", word)
+		}
+		sb.WriteByte(byte(v))
+	}
+	return sb.String(), nil
+}
+
+// values는 '>' 전까지의 숫자/불 값을 형식에 맞게 해석
+func (p *smlParser) values(item *SECSItem) error {
+	for p.skipSpace(); p.peek() != '>' && p.peek() != 0; p.skipSpace() {
+		word := p.token()
+		if word == "" {
+			return p.errorf("예상하지 못한 문자 %q", p.peek())
+		}
+		switch item.Format {
+		case "B":
+			v, err := strconv.ParseUint(word, 0, 8)
+			if err != nil {
+				return p.errorf("B 값은 0~255여야 합니다: %q", word)
+			}
+			item.Bytes = append(item.Bytes, byte(v))
+		case "BOOLEAN":
+			switch strings.ToUpper(word) {
+			case "T", "TRUE", "1", "0X01":
+				item.Bools = append(item.Bools, true)
+			case "F", "FALSE", "0", "0X00":
+				item.Bools = append(item.Bools, false)
+			default:
+				return p.errorf("BOOLEAN 값은 T 또는 F여야 합니다: %q", word)
+			}
+		case "U1", "U2", "U4", "U8":
+			v, err := strconv.ParseUint(word, 0, 8*item.size())
+			if err != nil {
+				return p.errorf("%s 값 오류: %q", item.Format, word)
+			}
+			item.Uints = append(item.Uints, v)
+		case "I1", "I2", "I4", "I8":
+			v, err := strconv.ParseInt(word, 0, 8*item.size())
+			if err != nil {
+				return p.errorf("%s 값 오류: %q", item.Format, word)
+			}
+			item.Ints = append(item.Ints, v)
+		case "F4", "F8":
+			v, err := strconv.ParseFloat(word, 8*item.size())
+			if err != nil {
+				return p.errorf("%s 값 오류: %q", item.Format, word)
+			}
+			item.Floats = append(item.Floats, v)
+		}
+	}
+	return nil
+}
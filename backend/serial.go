@@ -1,14 +1,12 @@
 package backend
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"go.bug.st/serial"
 	"io"
 	"os"
 	"runtime"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -24,90 +22,69 @@ func FindSerialPort() []string {
 	return ports
 }
 
-var (
-	managerOnce   sync.Once
-	managerSerial *serialManager
-)
-
-type serialManager struct {
-	connections   map[string]serial.Port
-	buffers       map[string]*bytes.Buffer
-	disconnecting map[string]bool
-	mu            sync.RWMutex
+func init() {
+	RegisterTransport("serial", newSerialTransport)
 }
 
-func getSerialManager() *serialManager {
-	managerOnce.Do(func() {
-		managerSerial = &serialManager{
-			connections:   make(map[string]serial.Port),
-			buffers:       make(map[string]*bytes.Buffer),
-			disconnecting: make(map[string]bool),
-		}
-		fmt.Println("SerialManager가 생성되었습니다.")
-	})
-	return managerSerial
+// serialTransport는 하나의 시리얼 포트 연결
+type serialTransport struct {
+	portName      string
+	line          SerialLineConfig
+	lines         *ModemLines // 포트를 열 때 적용할 DTR/RTS
+	port          serial.Port
+	events        TransportEvents
+	disconnecting bool
+	mu            sync.Mutex
 }
 
 // --- 공개 함수 ---
-func SerialConnect(portName string, baudRate int, onDataReceived func(port, dataType, data string)) error {
-	return getSerialManager().connect(portName, baudRate, onDataReceived)
-}
-
-func SerialDisconnect(portName string) error {
-	return getSerialManager().disconnect(portName)
+func SerialConnect(portName string, line SerialLineConfig, onEvent EventHandler) (string, error) {
+	return OpenSession(SessionConfig{Type: "serial", Address: portName, Serial: line}, onEvent)
 }
 
-func SerialSendData(portName, data string) error {
-	return getSerialManager().sendData(portName, data)
+func newSerialTransport(cfg SessionConfig) (Transport, error) {
+	if err := cfg.Serial.Validate(); err != nil {
+		return nil, fmt.Errorf("%s 회선 설정 오류: %v", cfg.Address, err)
+	}
+	return &serialTransport{portName: cfg.Address, line: cfg.Serial.normalize(), lines: cfg.ModemLines}, nil
 }
 
-// --- 비공개 함수 ---
-func (m *serialManager) connect(portName string, baudRate int, onDataReceived func(port, dataType, data string)) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+func (t *serialTransport) Open(events TransportEvents) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	if _, ok := m.connections[portName]; ok {
-		return nil
+	mode, err := t.line.mode()
+	if err != nil {
+		return fmt.Errorf("%s 회선 설정 오류: %v", t.portName, err)
 	}
-
-	mode := &serial.Mode{BaudRate: baudRate, DataBits: 8, Parity: serial.NoParity, StopBits: serial.OneStopBit}
-	port, err := serial.Open(portName, mode)
+	dtr, rts := t.lines.values()
+	mode.InitialStatusBits = &serial.ModemOutputBits{DTR: dtr, RTS: rts}
+	port, err := serial.Open(t.portName, mode)
 	if err != nil {
-		return fmt.Errorf("%s 포트 열기 실패: %v", portName, err)
+		return fmt.Errorf("%s 포트 열기 실패 (%s): %v", t.portName, t.line, err)
 	}
 
-	m.connections[portName] = port
-	m.buffers[portName] = new(bytes.Buffer)
-	m.disconnecting[portName] = false
-	fmt.Printf("%s 포트에 성공적으로 연결되었습니다.\n", portName)
+	t.port = port
+	t.events = events
+	t.disconnecting = false
+	fmt.Printf("%s 포트에 성공적으로 연결되었습니다. (%s)\n", t.portName, t.line)
 
-	go m.startReading(portName, onDataReceived, port)
+	go t.startReading(port)
 
 	return nil
 }
 
-func (m *serialManager) startReading(portName string, onDataReceived func(port, dataType, data string), port serial.Port) {
+func (t *serialTransport) startReading(port serial.Port) {
 	buff := make([]byte, 512)
+	var readErr error
 	for {
-		m.mu.RLock()
-		_, isConnected := m.connections[portName]
-		m.mu.RUnlock()
-		if !isConnected {
-			break
-		}
-
 		port.SetReadTimeout(1 * time.Second)
 		bytesRead, err := port.Read(buff)
 
 		if bytesRead > 0 {
-			m.mu.Lock()
-			buffer, ok := m.buffers[portName]
-			if ok && buffer != nil {
-				buffer.Write(buff[:bytesRead])
-			}
-			m.mu.Unlock()
-			// 버퍼 처리 함수 호출
-			m.processBuffer(portName, onDataReceived)
+			received := make([]byte, bytesRead)
+			copy(received, buff[:bytesRead])
+			t.events.OnReceive(received)
 		}
 		if err != nil {
 			if isTimeout(err) {
@@ -128,65 +105,127 @@ func (m *serialManager) startReading(portName string, onDataReceived func(port,
 					}
 				}
 			}
-			m.mu.Lock()
-			isDisconnecting, _ := m.disconnecting[portName]
-			m.mu.Unlock()
-			if !isDisconnecting && !isHandleError && err != io.EOF {
-				onDataReceived(portName, "ERRO", err.Error())
+			if !isHandleError && err != io.EOF {
+				readErr = err
 			}
 			break
 		}
 	}
 
-	_ = m.disconnect(portName)
+	t.mu.Lock()
+	isDisconnecting := t.disconnecting
+	t.mu.Unlock()
+	if isDisconnecting {
+		return
+	}
+	_ = t.Close()
+	t.events.OnClosed(readErr)
 }
 
-func (m *serialManager) disconnect(portName string) error {
-	m.mu.Lock()
-	m.disconnecting[portName] = true
-	port, ok := m.connections[portName]
-	m.mu.Unlock()
-	if !ok {
-		return fmt.Errorf("%s 포트는 연결되어 있지 않습니다", portName)
+// DefaultFraming은 시리얼 기본 수신 규칙 (CRLF 단위)
+func (t *serialTransport) DefaultFraming() FramingConfig {
+	return FramingConfig{Mode: "delimiter", Delimiter: "CRLF"}
+}
+
+func (t *serialTransport) Close() error {
+	t.mu.Lock()
+	if t.disconnecting || t.port == nil {
+		t.mu.Unlock()
+		return nil
 	}
+	t.disconnecting = true
+	port := t.port
+	t.mu.Unlock()
 
 	err := port.Close()
 	if runtime.GOOS == "windows" {
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	m.mu.Lock()
-	delete(m.buffers, portName)
-	delete(m.connections, portName)
-	m.mu.Unlock()
 	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, os.ErrClosed) {
-		return fmt.Errorf("%s 포트 닫기 실패: %v", portName, err)
+		return fmt.Errorf("%s 포트 닫기 실패: %v", t.portName, err)
 	}
 
-	fmt.Printf("%s 포트 연결이 해제되었습니다.\n", portName)
+	fmt.Printf("%s 포트 연결이 해제되었습니다.\n", t.portName)
 	return nil
 }
 
-func (m *serialManager) sendData(portName, data string) error {
-	m.mu.RLock()
-	port, ok := m.connections[portName]
-	m.mu.RUnlock()
-	if !ok {
-		return fmt.Errorf("%s 포트는 연결되어 있지 않습니다", portName)
+func (t *serialTransport) Write(data []byte) error {
+	t.mu.Lock()
+	port, closed := t.port, t.disconnecting
+	t.mu.Unlock()
+	if port == nil || closed {
+		return fmt.Errorf("%s 포트는 연결되어 있지 않습니다", t.portName)
 	}
 
-	_, err := port.Write([]byte(data + "\r\n"))
-	if err != nil {
-		if errors.Is(err, io.EOF) || errors.Is(err, os.ErrClosed) {
-			// 포트가 이미 닫힌 상태
-			go m.disconnect(portName)
+	if t.line.FlowControl == "rtscts" {
+		if err := waitClearToSend(port, 2*time.Second); err != nil {
+			return fmt.Errorf("%s 데이터 전송 실패: %v", t.portName, err)
 		}
-		return fmt.Errorf("%s 데이터 전송 실패: %v", portName, err)
 	}
-	fmt.Printf("[%s] 데이터 전송: '%s'\n", portName, data)
+
+	_, err := port.Write(data)
+	if err != nil {
+		return fmt.Errorf("%s 데이터 전송 실패: %v", t.portName, err)
+	}
+	fmt.Printf("[%s] 데이터 전송: %q\n", t.portName, data)
+	return nil
+}
+
+// SetLine은 열린 포트의 회선 설정을 바꿈
+func (t *serialTransport) SetLine(line SerialLineConfig) error {
+	mode, err := line.mode()
+	if err != nil {
+		return fmt.Errorf("%s 회선 설정 오류: %v", t.portName, err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.port == nil || t.disconnecting {
+		return fmt.Errorf("%s 포트는 연결되어 있지 않습니다", t.portName)
+	}
+	if err := t.port.SetMode(mode); err != nil {
+		return fmt.Errorf("%s 회선 설정 변경 실패: %v", t.portName, err)
+	}
+	t.line = line.normalize()
+	return nil
+}
+
+// SetModemLines는 DTR/RTS 출력을 바꿈
+func (t *serialTransport) SetModemLines(dtr, rts bool) error {
+	t.mu.Lock()
+	port, closed := t.port, t.disconnecting
+	t.mu.Unlock()
+	if port == nil || closed {
+		return fmt.Errorf("%s 포트는 연결되어 있지 않습니다", t.portName)
+	}
+	if err := port.SetDTR(dtr); err != nil {
+		return fmt.Errorf("%s DTR 설정 실패: %v", t.portName, err)
+	}
+	if err := port.SetRTS(rts); err != nil {
+		return fmt.Errorf("%s RTS 설정 실패: %v", t.portName, err)
+	}
 	return nil
 }
 
+// waitClearToSend는 "rtscts" 설정 시 전송 전에 상대방이 CTS를 올릴 때까지 대기
+// 드라이버가 하드웨어 흐름 제어를 지원하지 않아 Write 시작 시점에만 확인 (전송 중 CTS 변화는 반영하지 않음)
+func waitClearToSend(port serial.Port, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := port.GetModemStatusBits()
+		if err != nil {
+			return fmt.Errorf("CTS 상태 조회 실패: %v", err)
+		}
+		if status.CTS {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("CTS 대기 시간 초과 (%v)", timeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 func isTimeout(err error) bool {
 	// OS 수준의 타임아웃 오류
 	if errors.Is(err, os.ErrDeadlineExceeded) {
@@ -203,44 +242,3 @@ func isTimeout(err error) bool {
 	}
 	return false
 }
-
-func (m *serialManager) processBuffer(portName string, onDataReceived func(port, dataType, data string)) {
-	m.mu.Lock()
-	buffer, ok := m.buffers[portName]
-	m.mu.Unlock()
-
-	if !ok || buffer == nil {
-		return
-	}
-
-	delimiter := []byte("\r\n")
-
-	for {
-		m.mu.Lock()
-		bufferBytes := buffer.Bytes()
-		index := bytes.Index(bufferBytes, delimiter)
-		if index == -1 {
-			m.mu.Unlock()
-			break
-		}
-
-		messageBytes := make([]byte, index+len(delimiter))
-		_, err := buffer.Read(messageBytes) // 버퍼에서 읽기 (읽은 부분은 버퍼에서 제거)
-		isDisconnecting, _ := m.disconnecting[portName]
-		m.mu.Unlock()
-
-		if isDisconnecting {
-			break
-		} else {
-			if err != nil && err != io.EOF {
-				onDataReceived(portName, "ERRO", fmt.Sprintf("버퍼 읽기 오류: %v", err))
-				continue
-			}
-		}
-
-		message := strings.TrimSpace(string(messageBytes[:index]))
-		if len(message) > 0 {
-			onDataReceived(portName, "RECV", message)
-		}
-	}
-}
@@ -0,0 +1,198 @@
+package backend
+
+import (
+	"fmt"
+	"go.bug.st/serial"
+	"runtime"
+	"strings"
+)
+
+// SerialLineConfig는 시리얼 포트 회선 설정 (예: 9600 7E1, RTS/CTS)
+// 로컬 포트의 "rtscts"는 하드웨어 흐름 제어가 아니라 전송 전 CTS 확인 (버퍼 전송 중 CTS가 내려가도 멈추지 않음)
+// 원격 포트(rfc2217)는 터미널 서버의 하드웨어 흐름 제어를 켬
+type SerialLineConfig struct {
+	BaudRate    int    `json:"baudRate"`
+	DataBits    int    `json:"dataBits"`    // 5, 6, 7, 8
+	Parity      string `json:"parity"`      // "N", "E", "O", "M", "S"
+	StopBits    string `json:"stopBits"`    // "1", "1.5", "2"
+	FlowControl string `json:"flowControl"` // "none", "rtscts" (로컬: 전송 전 CTS 확인)
+}
+
+// DefaultSerialLineConfig는 기존 기본값(8N1, 흐름 제어 없음)을 반환
+func DefaultSerialLineConfig(baudRate int) SerialLineConfig {
+	return SerialLineConfig{BaudRate: baudRate, DataBits: 8, Parity: "N", StopBits: "1", FlowControl: "none"}
+}
+
+var serialParityMap = map[string]serial.Parity{
+	"N": serial.NoParity,
+	"O": serial.OddParity,
+	"E": serial.EvenParity,
+	"M": serial.MarkParity,
+	"S": serial.SpaceParity,
+}
+
+var serialStopBitsMap = map[string]serial.StopBits{
+	"1":   serial.OneStopBit,
+	"1.5": serial.OnePointFiveStopBits,
+	"2":   serial.TwoStopBits,
+}
+
+// normalize는 빈 값을 기본값으로 채우고 대소문자를 정리
+func (c SerialLineConfig) normalize() SerialLineConfig {
+	if c.DataBits == 0 {
+		c.DataBits = 8
+	}
+	c.Parity = strings.ToUpper(strings.TrimSpace(c.Parity))
+	if c.Parity == "" {
+		c.Parity = "N"
+	}
+	c.StopBits = strings.TrimSpace(c.StopBits)
+	if c.StopBits == "" {
+		c.StopBits = "1"
+	}
+	c.FlowControl = strings.ToLower(strings.TrimSpace(c.FlowControl))
+	if c.FlowControl == "" {
+		c.FlowControl = "none"
+	}
+	return c
+}
+
+// Validate는 지원하지 않는 조합을 미리 걸러냄
+func (c SerialLineConfig) Validate() error {
+	return c.validate(true)
+}
+
+// validate는 local이면 이 OS의 드라이버 제약까지 확인 (원격 포트는 터미널 서버가 판단)
+func (c SerialLineConfig) validate(local bool) error {
+	c = c.normalize()
+	if c.BaudRate <= 0 {
+		return fmt.Errorf("잘못된 Baud Rate 입니다: %d", c.BaudRate)
+	}
+	if c.DataBits < 5 || c.DataBits > 8 {
+		return fmt.Errorf("Data Bits는 5~8 이어야 합니다: %d", c.DataBits)
+	}
+	if _, ok := serialParityMap[c.Parity]; !ok {
+		return fmt.Errorf("지원하지 않는 Parity 입니다: %s (N, E, O, M, S)", c.Parity)
+	}
+	if local && (c.Parity == "M" || c.Parity == "S") && runtime.GOOS != "windows" && runtime.GOOS != "linux" {
+		return fmt.Errorf("%s 에서는 Mark/Space Parity를 지원하지 않습니다", runtime.GOOS)
+	}
+	if _, ok := serialStopBitsMap[c.StopBits]; !ok {
+		return fmt.Errorf("지원하지 않는 Stop Bits 입니다: %s (1, 1.5, 2)", c.StopBits)
+	}
+	if c.StopBits == "1.5" {
+		if local && runtime.GOOS != "windows" {
+			return fmt.Errorf("%s 에서는 1.5 Stop Bits를 지원하지 않습니다", runtime.GOOS)
+		}
+		if c.DataBits != 5 {
+			return fmt.Errorf("1.5 Stop Bits는 Data Bits 5 에서만 사용할 수 있습니다")
+		}
+	}
+	if local && c.StopBits == "2" && c.DataBits == 5 && runtime.GOOS == "windows" {
+		return fmt.Errorf("Data Bits 5 에서는 2 Stop Bits를 사용할 수 없습니다")
+	}
+	switch c.FlowControl {
+	case "none", "rtscts":
+	case "xonxoff":
+		return fmt.Errorf("XON/XOFF 흐름 제어는 지원하지 않습니다")
+	default:
+		return fmt.Errorf("지원하지 않는 흐름 제어 입니다: %s (none, rtscts)", c.FlowControl)
+	}
+	return nil
+}
+
+// mode는 검증된 설정을 serial.Mode로 변환
+func (c SerialLineConfig) mode() (*serial.Mode, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	c = c.normalize()
+	return &serial.Mode{
+		BaudRate:          c.BaudRate,
+		DataBits:          c.DataBits,
+		Parity:            serialParityMap[c.Parity],
+		StopBits:          serialStopBitsMap[c.StopBits],
+		InitialStatusBits: &serial.ModemOutputBits{RTS: true, DTR: true},
+	}, nil
+}
+
+// String은 "57600 8N1" 형태로 표시 (흐름 제어 사용 시 " RTS/CTS" 추가)
+func (c SerialLineConfig) String() string {
+	c = c.normalize()
+	s := fmt.Sprintf("%d %d%s%s", c.BaudRate, c.DataBits, c.Parity, c.StopBits)
+	if c.FlowControl == "rtscts" {
+		s += " RTS/CTS"
+	}
+	return s
+}
+
+// ModemLines는 시리얼 포트의 DTR/RTS 출력 상태
+type ModemLines struct {
+	DTR bool `json:"dtr"`
+	RTS bool `json:"rts"`
+}
+
+// values는 요청한 출력 상태 (요청이 없었으면 로컬 포트 기본값과 같이 DTR/RTS ON)
+func (m *ModemLines) values() (dtr, rts bool) {
+	if m == nil {
+		return true, true
+	}
+	return m.DTR, m.RTS
+}
+
+// lineController는 연결된 상태에서 회선 설정과 DTR/RTS를 바꿀 수 있는 전송 (serial, rfc2217)
+type lineController interface {
+	SetLine(line SerialLineConfig) error
+	SetModemLines(dtr, rts bool) error
+}
+
+// SessionSetLine은 열린 시리얼(원격 포함) 세션의 회선 설정을 바꿈 (재연결 시에도 새 설정 사용)
+func SessionSetLine(sessionID string, line SerialLineConfig) error {
+	session, controller, err := sessionLineController(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := controller.SetLine(line); err != nil {
+		return err
+	}
+	session.stateMu.Lock()
+	session.Config.Serial = line
+	session.stateMu.Unlock()
+	session.emit("INFO", fmt.Sprintf("회선 설정 변경: %s", line))
+	return nil
+}
+
+// SessionSetModemLines는 열린 시리얼(원격 포함) 세션의 DTR/RTS 출력을 바꿈 (재연결 시에도 다시 적용)
+func SessionSetModemLines(sessionID string, dtr, rts bool) error {
+	session, controller, err := sessionLineController(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := controller.SetModemLines(dtr, rts); err != nil {
+		return err
+	}
+	session.stateMu.Lock()
+	session.Config.ModemLines = &ModemLines{DTR: dtr, RTS: rts}
+	session.stateMu.Unlock()
+	session.emit("INFO", fmt.Sprintf("DTR=%s RTS=%s", onOff(dtr), onOff(rts)))
+	return nil
+}
+
+func sessionLineController(sessionID string) (*Session, lineController, error) {
+	session, err := getSessionManager().get(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	controller, ok := session.currentTransport().(lineController)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s 세션은 회선 제어를 지원하지 않습니다", sessionID)
+	}
+	return session, controller, nil
+}
+
+func onOff(on bool) string {
+	if on {
+		return "ON"
+	}
+	return "OFF"
+}
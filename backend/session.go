@@ -0,0 +1,426 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventHandler는 세션 이벤트를 상위(App)로 전달
+// dataType == {"RECV", "SENT", "ERRO", "INFO", "CLOSE", "RECONNECTING", "RESTORED", "GAVEUP"}
+type EventHandler func(sessionID, dataType, data string)
+
+// Session은 세션 ID로 관리되는 하나의 연결
+type Session struct {
+	ID         string
+	ParentID   string // 리슨 세션이 받아들인 클라이언트면 리슨 세션 ID
+	Config     SessionConfig
+	transport  Transport
+	onEvent    EventHandler
+	onRaw      RawHandler // 바이너리 모드에서만 호출
+	terminator []byte
+	framer     framer
+	flushDelay time.Duration
+	flushTimer *time.Timer
+	rxMu       sync.Mutex
+
+	// 재연결 상태 (stateMu로 보호)
+	reconnecting bool
+	sendQueue    []string
+	closed       chan struct{}
+	closeOnce    sync.Once
+	stateMu      sync.Mutex
+
+	// SendAndExpect 응답 대기 (expectMu로 요청을 한 번에 하나씩)와 수신 구독 (waiterMu로 보호)
+	waiter       *expectWaiter
+	listeners    map[int]func(string)
+	nextListener int
+	waiterMu     sync.Mutex
+	expectMu     sync.Mutex
+}
+
+// SessionInfo는 UI에 노출하는 세션 요약 정보
+type SessionInfo struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parentId"`
+	Type     string `json:"type"`
+	Address  string `json:"address"`
+}
+
+var (
+	sessionManagerOnce sync.Once
+	managerSession     *sessionManager
+)
+
+type sessionManager struct {
+	sessions map[string]*Session
+	nextID   int
+	mu       sync.Mutex
+}
+
+func getSessionManager() *sessionManager {
+	sessionManagerOnce.Do(func() {
+		managerSession = &sessionManager{
+			sessions: make(map[string]*Session),
+		}
+		fmt.Println("SessionManager가 생성되었습니다.")
+	})
+	return managerSession
+}
+
+// --- 공개 함수 ---
+func OpenSession(cfg SessionConfig, onEvent EventHandler) (string, error) {
+	return getSessionManager().open(cfg, onEvent, nil)
+}
+
+// OpenSessionRaw는 바이너리 모드 세션의 원본 바이트도 onRaw로 받음
+func OpenSessionRaw(cfg SessionConfig, onEvent EventHandler, onRaw RawHandler) (string, error) {
+	return getSessionManager().open(cfg, onEvent, onRaw)
+}
+
+func CloseSession(sessionID string) error {
+	return getSessionManager().close(sessionID)
+}
+
+func CloseAllSessions() []error {
+	return getSessionManager().closeAll()
+}
+
+func SessionSend(sessionID, data string) error {
+	return getSessionManager().send(sessionID, data)
+}
+
+func SessionList() []SessionInfo {
+	return getSessionManager().list()
+}
+
+// --- 비공개 메소드 ---
+func (m *sessionManager) open(cfg SessionConfig, onEvent EventHandler, onRaw RawHandler) (string, error) {
+	transport, err := newTransport(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	terminator, err := ParseTerminator(cfg.TxTerminator)
+	if err != nil {
+		return "", fmt.Errorf("종료 문자 설정 오류: %v", err)
+	}
+	if cfg.Binary && cfg.TxTerminator == "" {
+		terminator = nil // 바이너리 모드는 입력한 바이트만 전송
+	}
+	if mt, ok := transport.(messageTransport); ok && mt.MessageOriented() && cfg.TxTerminator == "" {
+		terminator = nil // 메시지 단위 전송 (SECS 등)
+	}
+	if defaulter, ok := transport.(framingDefaulter); ok && cfg.Framing == (FramingConfig{}) {
+		cfg.Framing = defaulter.DefaultFraming()
+	}
+	rxFramer, flushDelay, err := newFramer(cfg.Framing)
+	if err != nil {
+		return "", fmt.Errorf("프레이밍 설정 오류: %v", err)
+	}
+
+	m.mu.Lock()
+	m.nextID++
+	id := fmt.Sprintf("%s-%d", cfg.Type, m.nextID)
+	session := &Session{ID: id, Config: cfg, transport: transport, onEvent: onEvent, onRaw: onRaw,
+		terminator: terminator, framer: rxFramer, flushDelay: flushDelay, closed: make(chan struct{})}
+	m.sessions[id] = session
+	m.mu.Unlock()
+
+	err = transport.Open(m.transportEvents(session, transport))
+	if err != nil {
+		m.mu.Lock()
+		delete(m.sessions, id)
+		m.mu.Unlock()
+		return "", err
+	}
+
+	fmt.Printf("[%s] %s 세션이 열렸습니다.\n", id, cfg.Address)
+	return id, nil
+}
+
+func (m *sessionManager) close(sessionID string) error {
+	m.mu.Lock()
+	session, ok := m.sessions[sessionID]
+	delete(m.sessions, sessionID)
+	var children []string
+	for id, s := range m.sessions {
+		if s.ParentID == sessionID {
+			children = append(children, id)
+		}
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%s 세션이 존재하지 않습니다", sessionID)
+	}
+
+	for _, id := range children {
+		m.close(id)
+	}
+
+	session.closeOnce.Do(func() { close(session.closed) })
+	session.stopFlush()
+	session.rejectQueue("세션이 닫혀 전송을 취소했습니다")
+	if err := session.currentTransport().Close(); err != nil {
+		return err
+	}
+	fmt.Printf("[%s] 세션이 닫혔습니다.\n", sessionID)
+	return nil
+}
+
+func (m *sessionManager) closeAll() []error {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.sessions))
+	for id := range m.sessions {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	var errs []error
+	for _, id := range ids {
+		if err := m.close(id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (m *sessionManager) get(sessionID string) (*Session, error) {
+	m.mu.Lock()
+	session, ok := m.sessions[sessionID]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%s 세션은 연결되어 있지 않습니다", sessionID)
+	}
+	return session, nil
+}
+
+func (m *sessionManager) send(sessionID, data string) error {
+	session, err := m.get(sessionID)
+	if err != nil {
+		return err
+	}
+	if _, err = session.encode(data); err != nil {
+		return err
+	}
+
+	session.stateMu.Lock()
+	if session.reconnecting {
+		err = session.enqueue(data)
+		session.stateMu.Unlock()
+		if err == nil {
+			session.emit("INFO", fmt.Sprintf("재연결 후 전송 대기: %s", data))
+		}
+		return err
+	}
+	transport := session.transport
+	session.stateMu.Unlock()
+
+	return session.write(transport, data)
+}
+
+func (s *Session) write(transport Transport, data string) error {
+	payload, err := s.encode(data)
+	if err != nil {
+		return err
+	}
+	payload = append(payload, s.terminator...)
+	if err := transport.Write(payload); err != nil {
+		return err
+	}
+	if s.Config.Binary {
+		s.emitBytes("SENT", payload)
+	} else {
+		s.emit("SENT", data)
+	}
+	return nil
+}
+
+// encode는 전송 문자열을 바이트로 변환 (바이너리 모드면 16진수/이스케이프 해석)
+func (s *Session) encode(data string) ([]byte, error) {
+	if s.Config.Binary {
+		return ParsePayload(data)
+	}
+	return []byte(data), nil
+}
+
+func (s *Session) currentTransport() Transport {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	return s.transport
+}
+
+// currentConfig는 설정 사본 (SessionSetLine이 stateMu를 잡고 회선 설정을 바꿈)
+func (s *Session) currentConfig() SessionConfig {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	return s.Config
+}
+
+// transportEvents는 transport 하나에 묶인 콜백을 생성 (재연결 후 이전 transport의 이벤트는 무시)
+func (m *sessionManager) transportEvents(session *Session, transport Transport) TransportEvents {
+	return TransportEvents{
+		OnReceive: func(data []byte) { m.handleReceive(session, data) },
+		OnClosed:  func(err error) { m.handleClosed(session, transport, err) },
+		OnAccept: func(child Transport, peer string) TransportEvents {
+			return m.acceptChild(session, child, peer)
+		},
+		OnDatagram: func(data []byte, from string) { session.emitDatagram(data, from) },
+		OnInfo:     func(message string) { session.emit("INFO", message) },
+	}
+}
+
+// acceptChild는 리슨 세션이 받아들인 클라이언트를 자식 세션으로 등록
+// 자식 세션 로그에는 어느 클라이언트인지 앞에 붙이고, 자식 종료는 UI 연결 해제가 아닌 INFO로 알림
+func (m *sessionManager) acceptChild(parent *Session, child Transport, peer string) TransportEvents {
+	cfg := parent.Config
+	cfg.Address = peer
+	cfg.Reconnect = ReconnectPolicy{}
+	rxFramer, flushDelay, _ := newFramer(cfg.Framing) // 부모 세션에서 이미 검증됨
+
+	onEvent := func(sessionID, dataType, data string) {
+		if dataType == "CLOSE" {
+			dataType = "INFO"
+		}
+		parent.emitAs(sessionID, dataType, fmt.Sprintf("[%s] %s", peer, data))
+	}
+
+	session := &Session{ID: fmt.Sprintf("%s/%s", parent.ID, peer), ParentID: parent.ID, Config: cfg, transport: child,
+		onEvent: onEvent, onRaw: parent.onRaw, terminator: parent.terminator, framer: rxFramer, flushDelay: flushDelay, closed: make(chan struct{})}
+	m.mu.Lock()
+	m.sessions[session.ID] = session
+	m.mu.Unlock()
+
+	parent.emit("INFO", fmt.Sprintf("클라이언트 접속: %s (%s)", peer, session.ID))
+	return m.transportEvents(session, child)
+}
+
+func (m *sessionManager) list() []SessionInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	infos := make([]SessionInfo, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		infos = append(infos, SessionInfo{ID: s.ID, ParentID: s.ParentID, Type: s.Config.Type, Address: s.Config.Address})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// handleReceive는 수신 조각을 프레이밍 규칙에 따라 메시지로 만들어 전달
+func (m *sessionManager) handleReceive(session *Session, data []byte) {
+	session.rxMu.Lock()
+	frames := session.framer.Feed(data)
+	if session.flushDelay > 0 {
+		if session.flushTimer == nil {
+			session.flushTimer = time.AfterFunc(session.flushDelay, session.flushPending)
+		} else {
+			session.flushTimer.Reset(session.flushDelay)
+		}
+	}
+	session.rxMu.Unlock()
+
+	for _, frame := range frames {
+		session.emitFrame(frame)
+	}
+}
+
+// flushPending은 flush 대기 시간 동안 추가 수신이 없을 때 남은 데이터를 내보냄
+func (s *Session) flushPending() {
+	s.rxMu.Lock()
+	frame := s.framer.Flush()
+	s.rxMu.Unlock()
+	s.emitFrame(frame)
+}
+
+func (s *Session) stopFlush() {
+	s.rxMu.Lock()
+	if s.flushTimer != nil {
+		s.flushTimer.Stop()
+	}
+	s.rxMu.Unlock()
+}
+
+func (s *Session) emitFrame(frame []byte) {
+	if s.Config.Binary {
+		if len(frame) > 0 {
+			s.emitBytes("RECV", frame)
+			s.deliver(string(frame))
+		}
+		return
+	}
+	message := strings.TrimSpace(string(frame))
+	if len(message) > 0 {
+		s.emit("RECV", message)
+		s.deliver(message)
+	}
+}
+
+// emitDatagram은 데이터그램 하나를 송신 주소와 함께 한 메시지로 전달
+func (s *Session) emitDatagram(data []byte, from string) {
+	if s.Config.Binary {
+		if s.onRaw != nil {
+			s.onRaw(s.ID, "RECV", data)
+		}
+		s.emit("RECV", fmt.Sprintf("[%s] %s", from, HexDump(data)))
+		s.deliver(string(data))
+		return
+	}
+	message := strings.TrimSpace(string(data))
+	if len(message) > 0 {
+		s.emit("RECV", fmt.Sprintf("[%s] %s", from, message))
+		s.deliver(message)
+	}
+}
+
+// handleClosed는 사용자 요청이 아닌 이유로 연결이 끊어졌을 때 재연결하거나 세션을 정리
+func (m *sessionManager) handleClosed(session *Session, transport Transport, err error) {
+	m.mu.Lock()
+	current, ok := m.sessions[session.ID]
+	m.mu.Unlock()
+	if !ok || current != session || session.currentTransport() != transport {
+		return // 이미 사용자 요청으로 닫혔거나 교체된 연결
+	}
+
+	session.stopFlush()
+	session.flushPending()
+	if err != nil {
+		session.emit("ERRO", err.Error())
+	}
+
+	if session.Config.Reconnect.Enabled {
+		m.startReconnect(session)
+		return
+	}
+	m.finish(session)
+}
+
+// finish는 세션을 목록에서 제거하고 종료를 알림
+func (m *sessionManager) finish(session *Session) {
+	m.mu.Lock()
+	if current, ok := m.sessions[session.ID]; ok && current == session {
+		delete(m.sessions, session.ID)
+	}
+	m.mu.Unlock()
+	session.closeOnce.Do(func() { close(session.closed) })
+	session.emit("CLOSE", fmt.Sprintf("%s 연결이 종료되었습니다", session.Config.Address))
+}
+
+// emitBytes는 원본 바이트를 onRaw로, 16진수/ASCII 표시를 onEvent로 전달
+func (s *Session) emitBytes(dataType string, data []byte) {
+	if s.onRaw != nil {
+		s.onRaw(s.ID, dataType, data)
+	}
+	s.emit(dataType, HexDump(data))
+}
+
+func (s *Session) emit(dataType, data string) {
+	s.emitAs(s.ID, dataType, data)
+}
+
+func (s *Session) emitAs(sessionID, dataType, data string) {
+	if s.onEvent != nil {
+		s.onEvent(sessionID, dataType, data)
+	}
+}
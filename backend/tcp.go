@@ -9,122 +9,128 @@ import (
 	"time"
 )
 
-var (
-	tcpManagerOnce sync.Once
-	managerTCP     *tcpManager
-)
-
-type tcpManager struct {
-	connections map[string]net.Conn
-	mu          sync.Mutex
+func init() {
+	RegisterTransport("tcp", newTCPTransport)
 }
 
-// getTCPManager 함수는 TCP 매니저 인스턴스를 반환
-func getTCPManager() *tcpManager {
-	tcpManagerOnce.Do(func() {
-		managerTCP = &tcpManager{
-			connections: make(map[string]net.Conn),
-		}
-		fmt.Println("TCPManager가 생성되었습니다.")
-	})
-	return managerTCP
-}
+// tcpTransport는 하나의 TCP 클라이언트 연결
+type tcpTransport struct {
+	addr    string
+	conn    net.Conn
+	closing bool
+	mu      sync.Mutex
 
-// --- 공개 함수 ---
-func TCPConnect(ip string, onDataReceived func(addr, dataType, data string)) error {
-	return getTCPManager().connect(ip, onDataReceived)
+	onClose func() // 리슨 소켓이 받아들인 연결이면 클라이언트 목록에서 빼는 처리 (닫는 경로와 무관하게 한 번 호출)
 }
 
-func TCPDisconnect(ip string) error {
-	return getTCPManager().disconnect(ip)
+func newTCPTransport(cfg SessionConfig) (Transport, error) {
+	return &tcpTransport{addr: cfg.Address}, nil
 }
 
-func TCPSendData(ip string, data string) error {
-	return getTCPManager().sendData(ip, data)
+// newAcceptedTCPTransport는 리슨 소켓이 받아들인 연결을 감쌈 (Open 시 접속하지 않음)
+func newAcceptedTCPTransport(conn net.Conn) *tcpTransport {
+	return &tcpTransport{addr: conn.RemoteAddr().String(), conn: conn}
 }
 
-// --- 비공개 메소드 ---
-func (m *tcpManager) connect(addr string, onDataReceived func(addr, dataType, data string)) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+func (t *tcpTransport) Open(events TransportEvents) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	if _, ok := m.connections[addr]; ok {
-		return nil
-	}
-
-	conn, err := net.DialTimeout("tcp", addr, 3*time.Second)
-	if err != nil {
-		return fmt.Errorf("%s TCP 연결 실패: %v", addr, err)
+	conn := t.conn
+	if conn == nil {
+		var err error
+		conn, err = net.DialTimeout("tcp", t.addr, 3*time.Second)
+		if err != nil {
+			return fmt.Errorf("%s TCP 연결 실패: %v", t.addr, err)
+		}
+		fmt.Printf("%s 에 성공적으로 연결되었습니다.\n", t.addr)
 	}
 
-	m.connections[addr] = conn
-	fmt.Printf("%s 에 성공적으로 연결되었습니다.\n", addr)
+	t.conn = conn
+	t.closing = false
 
-	go m.startReading(addr, conn, onDataReceived)
+	go t.startReading(conn, events)
 
 	return nil
 }
 
-func (m *tcpManager) disconnect(addr string) error {
-	m.mu.Lock()
-	conn, ok := m.connections[addr]
-	m.mu.Unlock()
-	if !ok {
+// DefaultFraming은 TCP 기본 수신 규칙
+// 세그먼트 경계와 상관없이 CRLF 단위로 재조립하고, 구분자 없이 끝난 응답은 500ms 후 그대로 내보냄
+func (t *tcpTransport) DefaultFraming() FramingConfig {
+	return FramingConfig{Mode: "delimiter", Delimiter: "CRLF", FlushTimeoutMs: 500}
+}
+
+func (t *tcpTransport) Close() error {
+	t.mu.Lock()
+	conn := t.conn
+	if conn == nil || t.closing {
+		t.mu.Unlock()
 		return nil
 	}
+	t.closing = true
+	onClose := t.onClose
+	t.mu.Unlock()
 
+	if onClose != nil {
+		onClose()
+	}
 	err := conn.Close()
 	if err != nil && !errors.Is(err, net.ErrClosed) {
-		return fmt.Errorf("%s 연결 해제 실패: %v", addr, err)
+		return fmt.Errorf("%s 연결 해제 실패: %v", t.addr, err)
 	}
-	m.mu.Lock()
-	delete(m.connections, addr)
-	m.mu.Unlock()
 
-	fmt.Printf("%s 연결이 해제되었습니다.\n", addr)
+	fmt.Printf("%s 연결이 해제되었습니다.\n", t.addr)
 	return nil
 }
 
-func (m *tcpManager) sendData(addr string, data string) error {
-	m.mu.Lock()
-	conn, ok := m.connections[addr]
-	m.mu.Unlock()
-	if !ok {
-		return fmt.Errorf("%s 는 연결되어 있지 않습니다", addr)
+func (t *tcpTransport) Write(data []byte) error {
+	t.mu.Lock()
+	conn, closed := t.conn, t.closing
+	t.mu.Unlock()
+	if conn == nil || closed {
+		return fmt.Errorf("%s 는 연결되어 있지 않습니다", t.addr)
 	}
 
 	conn.SetWriteDeadline(time.Now().Add(2 * time.Second))
-	_, err := conn.Write([]byte(data + "\r\n"))
+	_, err := conn.Write(data)
 	if err != nil {
-		return fmt.Errorf("%s 데이터 전송 실패: %v", addr, err)
+		return fmt.Errorf("%s 데이터 전송 실패: %v", t.addr, err)
 	}
-	fmt.Printf("[%s] 데이터 전송: '%s'\n", addr, data)
+	fmt.Printf("[%s] 데이터 전송: %q\n", t.addr, data)
 	return nil
 }
 
-func (m *tcpManager) startReading(addr string, conn net.Conn, onDataReceived func(addr, dataType, data string)) {
+func (t *tcpTransport) startReading(conn net.Conn, events TransportEvents) {
 	buff := make([]byte, 4096)
 	for {
 		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
 		bytesRead, err := conn.Read(buff)
+		if bytesRead > 0 {
+			received := make([]byte, bytesRead)
+			copy(received, buff[:bytesRead])
+			events.OnReceive(received)
+		}
 		if err != nil {
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 				continue // 타임아웃이면 재시도
 			}
 
-			m.disconnect(addr)
+			t.mu.Lock()
+			isClosing := t.closing
+			t.mu.Unlock()
+			if isClosing {
+				return
+			}
+			t.Close()
 
 			// io.EOF는 정상적인 연결 종료
-			if !(errors.Is(err, net.ErrClosed) || err == io.EOF) {
-				fmt.Printf("[%s] 데이터 읽기 오류: %v\n", addr, err)
-				onDataReceived(addr, "ERRO", "연결이 비정상적으로 종료되었습니다: "+err.Error())
+			if errors.Is(err, net.ErrClosed) || err == io.EOF {
+				events.OnClosed(nil)
+			} else {
+				fmt.Printf("[%s] 데이터 읽기 오류: %v\n", t.addr, err)
+				events.OnClosed(fmt.Errorf("연결이 비정상적으로 종료되었습니다: %v", err))
 			}
 			return
 		}
-		if bytesRead > 0 {
-			receivedData := string(buff[:bytesRead])
-			// 콜백 함수에 주소(addr)도 함께 전달하여 어느 서버에서 온 데이터인지 구분
-			onDataReceived(addr, "RECV", receivedData)
-		}
 	}
 }
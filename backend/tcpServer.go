@@ -0,0 +1,141 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+func init() {
+	RegisterTransport("tcpserver", newTCPServerTransport)
+}
+
+// tcpServerTransport는 지정한 주소에서 접속을 기다리는 리슨 소켓
+// 받아들인 클라이언트는 각각 자식 세션이 되고, 리슨 세션으로 보낸 데이터는 모든 클라이언트에 전송
+type tcpServerTransport struct {
+	addr     string
+	listener net.Listener
+	clients  map[*tcpTransport]struct{}
+	closing  bool
+	mu       sync.Mutex
+}
+
+func newTCPServerTransport(cfg SessionConfig) (Transport, error) {
+	return &tcpServerTransport{addr: cfg.Address, clients: make(map[*tcpTransport]struct{})}, nil
+}
+
+// DefaultFraming은 자식 세션에도 그대로 적용 (TCP와 동일)
+func (t *tcpServerTransport) DefaultFraming() FramingConfig {
+	return FramingConfig{Mode: "delimiter", Delimiter: "CRLF", FlushTimeoutMs: 500}
+}
+
+func (t *tcpServerTransport) Open(events TransportEvents) error {
+	if events.OnAccept == nil {
+		return fmt.Errorf("%s 리슨 모드는 접속 처리기가 필요합니다", t.addr)
+	}
+
+	listener, err := net.Listen("tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("%s 리슨 실패: %v", t.addr, err)
+	}
+
+	t.mu.Lock()
+	t.listener = listener
+	t.closing = false
+	t.mu.Unlock()
+	fmt.Printf("%s 에서 접속 대기를 시작합니다.\n", t.addr)
+
+	go t.acceptLoop(listener, events)
+	return nil
+}
+
+func (t *tcpServerTransport) acceptLoop(listener net.Listener, events TransportEvents) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			t.mu.Lock()
+			isClosing := t.closing
+			t.mu.Unlock()
+			if isClosing || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			t.Close()
+			events.OnClosed(fmt.Errorf("%s 접속 대기 실패: %v", t.addr, err))
+			return
+		}
+
+		client := newAcceptedTCPTransport(conn)
+		// 사용자가 자식 세션을 닫거나 연결이 끊기면 브로드캐스트 대상에서 뺌
+		client.onClose = func() {
+			t.mu.Lock()
+			delete(t.clients, client)
+			t.mu.Unlock()
+		}
+		t.mu.Lock()
+		if t.closing {
+			t.mu.Unlock()
+			conn.Close()
+			return
+		}
+		t.clients[client] = struct{}{}
+		t.mu.Unlock()
+		fmt.Printf("%s 에 클라이언트 %s 가 접속했습니다.\n", t.addr, client.addr)
+
+		childEvents := events.OnAccept(client, client.addr)
+		if err = client.Open(childEvents); err != nil {
+			t.mu.Lock()
+			delete(t.clients, client)
+			t.mu.Unlock()
+			conn.Close()
+		}
+	}
+}
+
+// Close는 리슨 소켓과 접속한 모든 클라이언트를 닫음
+func (t *tcpServerTransport) Close() error {
+	t.mu.Lock()
+	listener := t.listener
+	if listener == nil || t.closing {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closing = true
+	clients := make([]*tcpTransport, 0, len(t.clients))
+	for c := range t.clients {
+		clients = append(clients, c)
+	}
+	t.clients = make(map[*tcpTransport]struct{})
+	t.mu.Unlock()
+
+	err := listener.Close()
+	for _, c := range clients {
+		c.Close()
+	}
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		return fmt.Errorf("%s 리슨 종료 실패: %v", t.addr, err)
+	}
+	fmt.Printf("%s 접속 대기를 종료했습니다.\n", t.addr)
+	return nil
+}
+
+// Write는 접속한 모든 클라이언트에 전송
+func (t *tcpServerTransport) Write(data []byte) error {
+	t.mu.Lock()
+	clients := make([]*tcpTransport, 0, len(t.clients))
+	for c := range t.clients {
+		clients = append(clients, c)
+	}
+	t.mu.Unlock()
+	if len(clients) == 0 {
+		return fmt.Errorf("%s 에 접속한 클라이언트가 없습니다", t.addr)
+	}
+
+	var errs []error
+	for _, c := range clients {
+		if err := c.Write(data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
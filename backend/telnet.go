@@ -3,299 +3,323 @@ package backend
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net"
-	"strings"
+	"regexp"
 	"sync"
 	"time"
 )
 
-// --- Telnet 매니저 싱글턴 ---
-
-var (
-	telnetManagerOnce sync.Once
-	managerTelnet     *telnetManager
-)
-
-// TelnetSession은 하나의 Telnet 연결 상태를 저장
-type TelnetSession struct {
-	Conn   net.Conn
-	Reader *bufio.Reader
-}
-
-// telnetManager 구조체 (private)
-type telnetManager struct {
-	sessions map[string]*TelnetSession
-	mu       sync.Mutex
-}
-
-// getTelnetManager는 Telnet 매니저 인스턴스를 반환
-func getTelnetManager() *telnetManager {
-	telnetManagerOnce.Do(func() {
-		managerTelnet = &telnetManager{
-			sessions: make(map[string]*TelnetSession),
-		}
-		fmt.Println("TelnetManager가 생성되었습니다.")
-	})
-	return managerTelnet
-}
-
-// --- 공개 함수 ---
-func TelnetConnect(ip string) error {
-	return getTelnetManager().connect(ip)
+func init() {
+	RegisterTransport("telnet", newTelnetTransport)
 }
 
-func TelnetDisconnect(ip string) error {
-	return getTelnetManager().disconnect(ip)
+// telnetTransport는 하나의 Telnet 연결 상태를 저장
+// 수신은 항상 백그라운드 고루틴이 읽고, 요청/응답 모드에서는 그 위에서 응답을 모아 한 메시지로 전달
+// (요청/응답 모드: 명령 하나를 보내면 응답을 모두 읽을 때까지 다음 명령은 대기)
+type telnetTransport struct {
+	addr    string
+	profile TelnetProfile
+	prompts *telnetPrompts
+	conn    net.Conn
+	proto   *telnetProtocol
+	reader  *bufio.Reader
+	events  TransportEvents
+	busy    chan struct{}
+	pending *telnetRequest
+	closing bool
+	mu      sync.Mutex
 }
 
-func TelnetSendData(ip string, command string) (string, error) {
-	return getTelnetManager().sendData(ip, command)
+// telnetRequest는 요청/응답 모드에서 응답을 기다리는 명령 하나
+type telnetRequest struct {
+	command  []byte
+	response []byte
+	finished bool
+	done     chan struct{}
 }
 
-// --- 비공개 메소드 ---
-func (m *telnetManager) connect(ip string) (err error) {
-	m.mu.Lock()
-	if _, ok := m.sessions[ip]; ok {
-		m.mu.Unlock()
-		return nil
+func newTelnetTransport(cfg SessionConfig) (Transport, error) {
+	profile, err := cfg.Telnet.resolve()
+	if err != nil {
+		return nil, err
 	}
-	m.sessions[ip] = nil
-	m.mu.Unlock()
+	prompts, err := profile.compile()
+	if err != nil {
+		return nil, err
+	}
+	return &telnetTransport{addr: cfg.Address, profile: profile, prompts: prompts, busy: make(chan struct{}, 1)}, nil
+}
 
-	defer func() {
-		if err != nil {
-			m.mu.Lock()
-			if session, ok := m.sessions[ip]; ok && session == nil {
-				delete(m.sessions, ip)
-			}
-			m.mu.Unlock()
-		}
-	}()
+func (t *telnetTransport) Open(events TransportEvents) (err error) {
 	for i := 0; i < 3; i++ {
-		fmt.Printf("Telnet 연결 시도 #%d for %s...\n", i+1, ip)
+		fmt.Printf("Telnet 연결 시도 #%d for %s...\n", i+1, t.addr)
 		var conn net.Conn
-		conn, err = net.DialTimeout("tcp", ip, 2*time.Second)
+		conn, err = net.DialTimeout("tcp", t.addr, 2*time.Second)
 		if err != nil {
 			time.Sleep(200 * time.Millisecond) // 실패 시 잠시 대기
 			continue                           // 다음 시도
 		}
 
-		// 연결에 성공하면, 실패 시 conn을 닫도록 defer 설정
-		success := false
-		defer func() {
-			if !success {
-				conn.Close()
-			}
-		}()
-
-		reader := bufio.NewReader(conn)
-
-		if err = skipTelnetNegotiation(conn, reader, 2*time.Second); err != nil {
-			fmt.Printf("협상 실패: %v\n", err)
+		proto := newTelnetProtocol(conn, "VT100", 80, 24)
+		// 서버 제안을 기다리지 않고 문자 모드(양방향 SGA, 서버 ECHO)와 창 크기/터미널 종류를 먼저 요청
+		proto.enable(telOptSGA, true)
+		proto.enable(telOptSGA, false)
+		proto.enable(telOptEcho, false)
+		proto.enable(telOptNAWS, true)
+		proto.enable(telOptTType, true)
+		var reader *bufio.Reader
+		reader, err = t.login(conn, proto)
+		if err != nil {
+			fmt.Println(err.Error())
+			conn.Close()
 			time.Sleep(200 * time.Millisecond)
 			continue
 		}
 
-		if _, err = readUntil(conn, reader, "Password: ", 2*time.Second); err != nil {
-			fmt.Printf("'Password:' 대기 실패: %v\n", err)
-			time.Sleep(200 * time.Millisecond)
-			continue
-		}
-		conn.Write([]byte("Help\r\n"))
+		t.mu.Lock()
+		t.conn = conn
+		t.proto = proto
+		t.reader = reader
+		t.events = events
+		t.closing = false
+		t.mu.Unlock()
+		fmt.Printf("%s 에 성공적으로 연결 및 인증되었습니다. (%s)\n", t.addr, t.profile.Name)
 
-		if _, err = readUntil(conn, reader, "GPL:", 2*time.Second); err != nil {
-			fmt.Printf("'GPL:' 대기 실패: %v\n", err)
-			time.Sleep(200 * time.Millisecond)
-			continue
+		go t.startReading(conn, reader)
+		return nil
+	}
+
+	return fmt.Errorf("%s Telnet 연결 실패: %v", t.addr, err)
+}
+
+// login은 프로필 순서대로 사용자 이름, 비밀번호 입력 후 프롬프트까지 대기
+// 옵션 협상은 proto가 수신 중에 처리
+func (t *telnetTransport) login(conn net.Conn, proto *telnetProtocol) (*bufio.Reader, error) {
+	reader := bufio.NewReader(proto)
+	timeout := t.prompts.loginTimeout
+
+	if t.prompts.user != nil {
+		if _, err := readUntil(conn, reader, t.prompts.user, timeout); err != nil {
+			return nil, fmt.Errorf("'%s' 대기 실패: %v", t.profile.UserPrompt, err)
 		}
+		proto.Write([]byte(t.profile.Username + "\r\n"))
+	}
 
-		m.mu.Lock()
-		if _, ok := m.sessions[ip]; ok && m.sessions[ip] == nil {
-			session := &TelnetSession{Conn: conn, Reader: reader}
-			m.sessions[ip] = session
-			fmt.Printf("%s 에 성공적으로 연결 및 인증되었습니다.\n", ip)
-			success = true // 성공했으므로 defer에서 conn을 닫지 않도록 플래그 설정
-		} else {
-			// 그 사이에 연결 해제 요청이 들어온 경우
-			err = fmt.Errorf("[%s] 연결 해제 요청", ip)
+	if t.prompts.password != nil {
+		if _, err := readUntil(conn, reader, t.prompts.password, timeout); err != nil {
+			return nil, fmt.Errorf("'%s' 대기 실패: %v", t.profile.PasswordPrompt, err)
 		}
-		m.mu.Unlock()
-		break
+		proto.Write([]byte(t.profile.Password + "\r\n"))
 	}
 
-	return err
+	if _, err := readUntil(conn, reader, t.prompts.ready, timeout); err != nil {
+		return nil, fmt.Errorf("'%s' 대기 실패: %v", t.profile.ReadyPrompt, err)
+	}
+	return reader, nil
 }
 
-func (m *telnetManager) disconnect(ip string) error {
-	m.mu.Lock()
-	session, ok := m.sessions[ip]
-	m.mu.Unlock()
-	if !ok {
-		return nil // 이미 연결 끊김
+// DefaultFraming은 Telnet 기본 수신 규칙
+// 요청/응답 모드는 응답 하나가 한 메시지, 스트리밍 모드는 줄 단위 (프롬프트처럼 줄바꿈 없는 출력은 500ms 후 전달)
+func (t *telnetTransport) DefaultFraming() FramingConfig {
+	if t.profile.Streaming {
+		return FramingConfig{Mode: "delimiter", Delimiter: "CRLF", FlushTimeoutMs: 500}
 	}
+	return FramingConfig{Mode: "none"}
+}
 
-	if session.Conn != nil {
-		if tcpConn, ok := session.Conn.(*net.TCPConn); ok {
-			tcpConn.SetLinger(0)
-		}
-		session.Conn.Close()
+func (t *telnetTransport) Close() error {
+	t.mu.Lock()
+	conn := t.conn
+	if conn == nil || t.closing {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closing = true
+	t.mu.Unlock()
+
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		tcpConn.SetLinger(0)
 	}
+	conn.Close()
 
-	m.mu.Lock()
-	delete(m.sessions, ip)
-	m.mu.Unlock()
-	fmt.Printf("%s Telnet 연결이 해제되었습니다.\n", ip)
+	fmt.Printf("%s Telnet 연결이 해제되었습니다.\n", t.addr)
 	return nil
 }
 
-// sendData는 명령어를 보내고 응답을 기다려 반환
-func (m *telnetManager) sendData(ip string, command string) (string, error) {
-	m.mu.Lock()
-	session, ok := m.sessions[ip]
-	m.mu.Unlock()
-	if !ok {
-		return "", fmt.Errorf("%s 는 연결되어 있지 않습니다", ip)
+// Write는 명령어를 전송
+// 요청/응답 모드에서는 응답 수집을 등록하고, 응답은 수신 고루틴이 모아 OnReceive로 전달
+func (t *telnetTransport) Write(data []byte) error {
+	if t.profile.Streaming {
+		return t.write(data)
 	}
 
-	clearInitialBuffer(session.Conn)
-
-	if _, err := session.Conn.Write([]byte(command + "\r\n")); err != nil {
-		m.disconnect(ip)
-		return "", fmt.Errorf("[%s] 명령어 전송 실패: %v", ip, err)
+	t.busy <- struct{}{}
+	req := &telnetRequest{command: bytes.TrimSpace(data), done: make(chan struct{})}
+	t.mu.Lock()
+	t.pending = req
+	t.mu.Unlock()
+
+	if err := t.write(data); err != nil {
+		t.mu.Lock()
+		t.pending = nil
+		t.mu.Unlock()
+		<-t.busy
+		return err
 	}
-	fmt.Printf("[%s] Telnet 명령어 전송: '%s'\n", ip, command)
 
-	// 응답 읽기
-	response, err := m.readAllResponse(session, 5*time.Second)
-	if err != nil {
-		m.disconnect(ip)
-		return "", fmt.Errorf("[%s] 응답 읽기 실패: %v", ip, err)
-	}
+	go t.awaitResponse(req, 5*time.Second)
+	return nil
+}
 
-	idx := strings.Index(response, "\n")
-	if idx != -1 {
-		response = response[idx+1:]
-	} else {
-		response = ""
+func (t *telnetTransport) write(data []byte) error {
+	t.mu.Lock()
+	conn, closed := t.conn, t.closing
+	t.mu.Unlock()
+	if conn == nil || closed {
+		return fmt.Errorf("%s 는 연결되어 있지 않습니다", t.addr)
 	}
 
-	fmt.Printf("[%s] Telnet 응답 수신\n", ip)
-	return strings.TrimSpace(response), nil
+	if _, err := t.proto.Write(data); err != nil {
+		t.fail(fmt.Errorf("[%s] 명령어 전송 실패: %v", t.addr, err))
+		return fmt.Errorf("[%s] 명령어 전송 실패: %v", t.addr, err)
+	}
+	fmt.Printf("[%s] Telnet 명령어 전송: %q\n", t.addr, data)
+	return nil
 }
 
-func clearInitialBuffer(conn net.Conn) error {
-	// 버퍼를 비우는 작업은 최대 0.5초만 시도
-	overallDeadline := time.Now().Add(500 * time.Millisecond)
-	readBuf := make([]byte, 1024) // 데이터를 읽어 담아둘 임시 버퍼
+// awaitResponse는 응답 완료 또는 시간 초과까지 대기 (시간 초과 시 모인 만큼 전달)
+func (t *telnetTransport) awaitResponse(req *telnetRequest, timeout time.Duration) {
+	defer func() { <-t.busy }()
 
-	for time.Now().Before(overallDeadline) {
-		err := conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
-		if err != nil {
-			return err
-		}
+	select {
+	case <-req.done:
+		return
+	case <-time.After(timeout):
+	}
 
-		_, err = conn.Read(readBuf)
-		if err != nil {
-			// 타임아웃 에러가 발생시, 더 이상 읽을 데이터가 없음
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				// 최종적으로 ReadDeadline을 초기화하여 이후 작업에 영향이 없도록 함
-				conn.SetReadDeadline(time.Time{})
-				return nil
-			}
-			if err == io.EOF {
-				return nil
-			}
-			// 그 외의 에러는 실제 연결 문제일 수 있으므로 에러 반환
-			return err
-		}
-		// 데이터가 읽혔다면, 버퍼에 더 있을 수 있으므로 루프 계속
+	t.mu.Lock()
+	if req.finished {
+		t.mu.Unlock()
+		return
 	}
-	return nil
+	req.finished = true
+	t.pending = nil
+	response := req.response
+	t.mu.Unlock()
+
+	fmt.Printf("[%s] Telnet 응답 대기 시간 초과\n", t.addr)
+	t.emitResponse(req.command, response)
 }
 
-func (m *telnetManager) readAllResponse(session *TelnetSession, timeout time.Duration) (string, error) {
-	var buf [4096]byte
-	var response strings.Builder
-	deadline := time.Now().Add(timeout)
-	search := []byte("GPL:")
-	var leftover []byte
-
-	for time.Now().Before(deadline) {
-		session.Conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
-		n, err := session.Reader.Read(buf[:])
-		if n > 0 {
-			cur := append(leftover, buf[:n]...)
-			if idx := bytes.Index(cur, search); idx >= 0 {
-				response.Write(cur[:idx])
-				break
-			}
-			response.Write(cur)
-			if len(cur) >= 4 {
-				leftover = cur[len(cur)-4:]
-			} else {
-				leftover = cur
-			}
+// startReading은 연결이 끊길 때까지 수신하여 dispatch로 넘김
+func (t *telnetTransport) startReading(conn net.Conn, reader *bufio.Reader) {
+	buff := make([]byte, 4096)
+	for {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		bytesRead, err := reader.Read(buff)
+		if bytesRead > 0 {
+			t.dispatch(buff[:bytesRead])
 		}
 		if err != nil {
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				continue // 타임아웃은 정상적인 대기 상태이므로 계속 진행
+				continue // 타임아웃이면 재시도
 			}
-			return response.String(), err // 그 외의 에러는 반환
+			if errors.Is(err, net.ErrClosed) || err == io.EOF {
+				t.fail(nil)
+			} else {
+				t.fail(fmt.Errorf("[%s] 응답 읽기 실패: %v", t.addr, err))
+			}
+			return
 		}
 	}
-	return response.String(), nil
 }
 
-func readUntil(conn net.Conn, reader *bufio.Reader, delim string, timeout time.Duration) (string, error) {
-	var buf strings.Builder
-	delimLen := len(delim)
-	conn.SetReadDeadline(time.Now().Add(timeout))
-	defer conn.SetReadDeadline(time.Time{})
+// dispatch는 대기 중인 요청이 있으면 응답으로 모으고, 없으면(알람, 프로그램 출력 등) 바로 전달
+func (t *telnetTransport) dispatch(data []byte) {
+	t.mu.Lock()
+	req := t.pending
+	if req == nil {
+		t.mu.Unlock()
+		received := make([]byte, len(data))
+		copy(received, data)
+		t.events.OnReceive(received)
+		return
+	}
 
-	for {
-		b, err := reader.ReadByte()
-		if err != nil {
-			return "", err
-		}
-		if b == 0xFF { // IAC
-			if reader.Buffered() < 2 {
-				continue
-			}
-			reader.Discard(2)
-			continue
-		}
-		buf.WriteByte(b)
+	req.response = append(req.response, data...)
+	loc := t.prompts.responseEnd.FindIndex(req.response)
+	if loc == nil {
+		t.mu.Unlock()
+		return
+	}
+	rest := append([]byte(nil), req.response[loc[1]:]...)
+	response := req.response[:loc[0]]
+	req.finished = true
+	t.pending = nil
+	close(req.done)
+	t.mu.Unlock()
+
+	fmt.Printf("[%s] Telnet 응답 수신\n", t.addr)
+	t.emitResponse(req.command, response)
+	if len(rest) > 0 {
+		t.events.OnReceive(rest)
+	}
+}
 
-		if buf.Len() >= delimLen && buf.String()[buf.Len()-delimLen:] == delim {
-			return buf.String(), nil
+// emitResponse는 에코된 명령어 줄을 제외한 응답을 전달
+// 에코 줄을 찾지 못하면 첫 줄을 에코로 간주
+func (t *telnetTransport) emitResponse(command, response []byte) {
+	lines := bytes.SplitAfter(response, []byte("\n"))
+	echo := 0
+	for i, line := range lines {
+		if len(command) > 0 && bytes.Equal(bytes.TrimSpace(line), command) {
+			echo = i
+			break
 		}
 	}
+	if len(lines) == 1 && !bytes.HasSuffix(response, []byte("\n")) {
+		lines = nil // 줄바꿈이 없으면 에코만 온 것
+	} else {
+		lines = append(lines[:echo], lines[echo+1:]...)
+	}
+	t.events.OnReceive(bytes.Join(lines, nil))
+}
+
+// fail은 통신 오류 시 연결을 끊고 상위에 알림
+func (t *telnetTransport) fail(err error) {
+	t.mu.Lock()
+	isClosing := t.closing
+	t.mu.Unlock()
+	if isClosing {
+		return
+	}
+	t.Close()
+	t.events.OnClosed(err)
 }
 
-func skipTelnetNegotiation(conn net.Conn, reader *bufio.Reader, timeout time.Duration) error {
+// readUntil은 최근 수신 내용이 prompt 정규식과 일치할 때까지 읽음
+func readUntil(conn net.Conn, reader *bufio.Reader, prompt *regexp.Regexp, timeout time.Duration) (string, error) {
+	const window = 256 // 프롬프트 비교에 사용할 최근 바이트 수
+	var buf []byte
 	conn.SetReadDeadline(time.Now().Add(timeout))
 	defer conn.SetReadDeadline(time.Time{})
 
 	for {
-		b, err := reader.Peek(1)
+		b, err := reader.ReadByte()
 		if err != nil {
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				// 데이터를 하나도 못받고 타임아웃 -> 협상할 내용이 없는 것으로 간주하고 성공 처리
-				return nil
-			}
-			return err
+			return "", err
 		}
+		buf = append(buf, b)
 
-		if b[0] == 0xFF {
-			if reader.Buffered() < 3 {
-				time.Sleep(10 * time.Millisecond)
-				continue
-			}
-			reader.Discard(3)
-		} else {
-			return nil // 협상 코드가 아니면 즉시 종료
+		tail := buf
+		if len(tail) > window {
+			tail = tail[len(tail)-window:]
+		}
+		if prompt.Match(tail) {
+			return string(buf), nil
 		}
 	}
 }
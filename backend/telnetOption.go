@@ -0,0 +1,393 @@
+package backend
+
+import (
+	"fmt"
+	"net"
+	"sync"
+)
+
+// Telnet 명령 (RFC 854)
+const (
+	telnetSE   byte = 240
+	telnetSB   byte = 250
+	telnetWILL byte = 251
+	telnetWONT byte = 252
+	telnetDO   byte = 253
+	telnetDONT byte = 254
+	telnetIAC  byte = 255
+)
+
+// 지원하는 Telnet 옵션
+const (
+	telOptBinary  byte = 0  // RFC 856
+	telOptEcho    byte = 1  // RFC 857
+	telOptSGA     byte = 3  // RFC 858
+	telOptTType   byte = 24 // RFC 1091
+	telOptNAWS    byte = 31 // RFC 1073
+	telOptComPort byte = 44 // RFC 2217
+)
+
+const (
+	ttypeIS   byte = 0
+	ttypeSEND byte = 1
+)
+
+// RFC 1143 Q method 상태
+type qState int
+
+const (
+	qNo qState = iota
+	qYes
+	qWantNo
+	qWantYes
+)
+
+// optionState는 옵션 하나에 대한 양쪽(us: 이쪽, him: 상대) 상태와 대기 큐
+type optionState struct {
+	us, him             qState
+	usOpposite          bool
+	himOpposite         bool
+	acceptUs, acceptHim bool // 상대가 요청했을 때 허용할지
+}
+
+// 수신 파서 상태
+const (
+	parseData = iota
+	parseCR
+	parseIAC
+	parseCommand
+	parseSB
+	parseSBIAC
+)
+
+// telnetProtocol은 net.Conn 위에서 IAC 협상과 이스케이프를 처리
+// Read는 순수 데이터만 돌려주고, 협상 응답은 내부에서 바로 전송
+type telnetProtocol struct {
+	conn     net.Conn
+	options  map[byte]*optionState
+	termType string
+	width    uint16
+	height   uint16
+
+	state   int
+	command byte
+	sbData  []byte
+
+	// onComPort는 COM-PORT-OPTION 부협상 (명령 바이트부터) 수신 시 호출 (decode 안에서 호출되므로 짧게 처리)
+	onComPort func(data []byte)
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+}
+
+func newTelnetProtocol(conn net.Conn, termType string, width, height uint16) *telnetProtocol {
+	p := &telnetProtocol{
+		conn:     conn,
+		options:  make(map[byte]*optionState),
+		termType: termType,
+		width:    width,
+		height:   height,
+	}
+	// 상대의 ECHO/SGA는 받아들이고, 이쪽은 SGA/NAWS/TTYPE만 허용 (ECHO는 하지 않음)
+	p.options[telOptEcho] = &optionState{acceptHim: true}
+	p.options[telOptSGA] = &optionState{acceptUs: true, acceptHim: true}
+	p.options[telOptNAWS] = &optionState{acceptUs: true}
+	p.options[telOptTType] = &optionState{acceptUs: true}
+	return p
+}
+
+// binary는 BINARY 전송(RFC 856)이 켜져 있는지 (us == 송신, !us == 수신, p.mu를 잡은 상태에서 호출)
+// 켜져 있으면 CR NUL 변환을 하지 않음
+func (p *telnetProtocol) binary(us bool) bool {
+	o, ok := p.options[telOptBinary]
+	if !ok {
+		return false
+	}
+	if us {
+		return o.us == qYes
+	}
+	return o.him == qYes
+}
+
+// enable은 이쪽에서 옵션을 먼저 요청 (us면 WILL, 아니면 DO), 결과는 enabled로 확인
+func (p *telnetProtocol) enable(opt byte, us bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	o := p.option(opt)
+	state, opposite, cmd := &o.him, &o.himOpposite, telnetDO
+	if us {
+		state, opposite, cmd = &o.us, &o.usOpposite, telnetWILL
+	}
+	switch *state {
+	case qNo:
+		*state = qWantYes
+		p.sendCommand(cmd, opt)
+	case qWantNo:
+		*opposite = true
+	}
+}
+
+// enabled는 옵션이 협상되어 켜졌는지
+func (p *telnetProtocol) enabled(opt byte, us bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	o := p.option(opt)
+	if us {
+		return o.us == qYes
+	}
+	return o.him == qYes
+}
+
+func (p *telnetProtocol) option(opt byte) *optionState {
+	o, ok := p.options[opt]
+	if !ok {
+		o = &optionState{}
+		p.options[opt] = o
+	}
+	return o
+}
+
+// Read는 수신 스트림에서 Telnet 명령을 걸러내고 데이터만 반환
+func (p *telnetProtocol) Read(b []byte) (int, error) {
+	raw := make([]byte, len(b))
+	for {
+		n, err := p.conn.Read(raw)
+		out := p.decode(raw[:n], b[:0])
+		if len(out) > 0 || err != nil {
+			return len(out), err
+		}
+	}
+}
+
+// decode는 raw를 해석해 데이터 바이트를 out에 추가 (len(out) <= len(raw))
+func (p *telnetProtocol) decode(raw, out []byte) []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, c := range raw {
+		switch p.state {
+		case parseData:
+			switch {
+			case c == telnetIAC:
+				p.state = parseIAC
+			case c == '\r' && !p.binary(false):
+				out = append(out, c)
+				p.state = parseCR
+			default:
+				out = append(out, c)
+			}
+		case parseCR:
+			// CR NUL은 CR 하나로 (RFC 854)
+			p.state = parseData
+			switch c {
+			case 0:
+			case telnetIAC:
+				p.state = parseIAC
+			default:
+				out = append(out, c)
+			}
+		case parseIAC:
+			switch c {
+			case telnetIAC:
+				out = append(out, telnetIAC) // IAC IAC == 데이터 0xFF
+				p.state = parseData
+			case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+				p.command = c
+				p.state = parseCommand
+			case telnetSB:
+				p.sbData = p.sbData[:0]
+				p.state = parseSB
+			default:
+				p.state = parseData // NOP, GA 등은 무시
+			}
+		case parseCommand:
+			p.handleOption(p.command, c)
+			p.state = parseData
+		case parseSB:
+			if c == telnetIAC {
+				p.state = parseSBIAC
+			} else {
+				p.sbData = append(p.sbData, c)
+			}
+		case parseSBIAC:
+			switch c {
+			case telnetSE:
+				p.handleSubnegotiation(p.sbData)
+				p.state = parseData
+			case telnetIAC:
+				p.sbData = append(p.sbData, telnetIAC)
+				p.state = parseSB
+			default:
+				p.state = parseData // 잘못된 시퀀스, 버림
+			}
+		}
+	}
+	return out
+}
+
+// handleOption은 RFC 1143 Q method에 따라 WILL/WONT/DO/DONT에 응답
+func (p *telnetProtocol) handleOption(cmd, opt byte) {
+	o := p.option(opt)
+	switch cmd {
+	case telnetWILL:
+		switch o.him {
+		case qNo:
+			if o.acceptHim {
+				o.him = qYes
+				p.sendCommand(telnetDO, opt)
+			} else {
+				p.sendCommand(telnetDONT, opt)
+			}
+		case qWantNo:
+			if o.himOpposite {
+				o.him, o.himOpposite = qYes, false
+			} else {
+				o.him = qNo // DONT에 WILL로 답한 경우
+			}
+		case qWantYes:
+			if o.himOpposite {
+				o.him, o.himOpposite = qWantNo, false
+				p.sendCommand(telnetDONT, opt)
+			} else {
+				o.him = qYes
+			}
+		}
+	case telnetWONT:
+		switch o.him {
+		case qYes:
+			o.him = qNo
+			p.sendCommand(telnetDONT, opt)
+		case qWantNo:
+			if o.himOpposite {
+				o.him, o.himOpposite = qWantYes, false
+				p.sendCommand(telnetDO, opt)
+			} else {
+				o.him = qNo
+			}
+		case qWantYes:
+			o.him, o.himOpposite = qNo, false
+		}
+	case telnetDO:
+		switch o.us {
+		case qNo:
+			if o.acceptUs {
+				o.us = qYes
+				p.sendCommand(telnetWILL, opt)
+				p.onUsEnabled(opt)
+			} else {
+				p.sendCommand(telnetWONT, opt)
+			}
+		case qWantNo:
+			if o.usOpposite {
+				o.us, o.usOpposite = qYes, false
+				p.onUsEnabled(opt)
+			} else {
+				o.us = qNo
+			}
+		case qWantYes:
+			if o.usOpposite {
+				o.us, o.usOpposite = qWantNo, false
+				p.sendCommand(telnetWONT, opt)
+			} else {
+				o.us = qYes
+				p.onUsEnabled(opt)
+			}
+		}
+	case telnetDONT:
+		switch o.us {
+		case qYes:
+			o.us = qNo
+			p.sendCommand(telnetWONT, opt)
+		case qWantNo:
+			if o.usOpposite {
+				o.us, o.usOpposite = qWantYes, false
+				p.sendCommand(telnetWILL, opt)
+			} else {
+				o.us = qNo
+			}
+		case qWantYes:
+			o.us, o.usOpposite = qNo, false
+		}
+	}
+}
+
+// onUsEnabled는 이쪽 옵션이 켜졌을 때 필요한 후속 전송
+func (p *telnetProtocol) onUsEnabled(opt byte) {
+	if opt == telOptNAWS {
+		p.sendWindowSize()
+	}
+}
+
+func (p *telnetProtocol) sendWindowSize() {
+	size := []byte{byte(p.width >> 8), byte(p.width), byte(p.height >> 8), byte(p.height)}
+	p.sendSubnegotiation(telOptNAWS, size)
+}
+
+func (p *telnetProtocol) handleSubnegotiation(data []byte) {
+	if len(data) < 2 {
+		return
+	}
+	if data[0] == telOptTType && data[1] == ttypeSEND && p.option(telOptTType).us == qYes {
+		p.sendSubnegotiation(telOptTType, append([]byte{ttypeIS}, p.termType...))
+	}
+	if data[0] == telOptComPort && p.onComPort != nil {
+		p.onComPort(data[1:])
+	}
+}
+
+func (p *telnetProtocol) sendCommand(cmd, opt byte) {
+	p.writeRaw([]byte{telnetIAC, cmd, opt})
+}
+
+func (p *telnetProtocol) sendSubnegotiation(opt byte, data []byte) {
+	msg := []byte{telnetIAC, telnetSB, opt}
+	msg = append(msg, escapeIAC(data)...)
+	msg = append(msg, telnetIAC, telnetSE)
+	p.writeRaw(msg)
+}
+
+func (p *telnetProtocol) writeRaw(b []byte) {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	if _, err := p.conn.Write(b); err != nil {
+		fmt.Printf("Telnet 협상 응답 전송 실패: %v\n", err)
+	}
+}
+
+// Write는 데이터의 0xFF를 IAC IAC로, 단독 CR(끝의 CR 포함)을 CR NUL로 바꿔 전송 (BINARY 전송이면 CR은 그대로)
+func (p *telnetProtocol) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	binary := p.binary(true)
+	p.mu.Unlock()
+
+	encoded := make([]byte, 0, len(b)+8)
+	for i, c := range b {
+		switch {
+		case c == telnetIAC:
+			encoded = append(encoded, telnetIAC, telnetIAC)
+		case c == '\r' && !binary && (i+1 == len(b) || b[i+1] != '\n'):
+			encoded = append(encoded, '\r', 0)
+		default:
+			encoded = append(encoded, c)
+		}
+	}
+
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	if _, err := p.conn.Write(encoded); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// escapeIAC는 부협상 데이터 안의 0xFF를 두 번 씀
+func escapeIAC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for _, c := range data {
+		if c == telnetIAC {
+			out = append(out, telnetIAC)
+		}
+		out = append(out, c)
+	}
+	return out
+}
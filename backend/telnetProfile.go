@@ -0,0 +1,131 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// TelnetProfile은 장비별 Telnet 로그인 순서와 프롬프트 규칙
+// 프롬프트와 ResponseEnd는 모두 정규식
+type TelnetProfile struct {
+	Name           string `json:"name"`
+	UserPrompt     string `json:"userPrompt"` // 비어 있으면 사용자 이름 단계 생략
+	Username       string `json:"username"`
+	PasswordPrompt string `json:"passwordPrompt"` // 비어 있으면 비밀번호 단계 생략
+	Password       string `json:"password"`
+	ReadyPrompt    string `json:"readyPrompt"` // 로그인 완료 프롬프트
+	ResponseEnd    string `json:"responseEnd"` // 응답 끝 표시, 비어 있으면 ReadyPrompt
+	LoginTimeoutMs int    `json:"loginTimeoutMs"`
+	Streaming      bool   `json:"streaming"` // true면 모든 출력을 줄 단위로 바로 전달 (요청/응답 대기 없음)
+}
+
+// 기본 제공 프로필, Name이 비어 있으면 GPL을 사용
+var builtinTelnetProfiles = []TelnetProfile{
+	{
+		Name:           "GPL",
+		PasswordPrompt: `Password: `,
+		Password:       "Help",
+		ReadyPrompt:    `GPL:`,
+	},
+	{
+		Name:           "Linux",
+		UserPrompt:     `login: ?$`,
+		PasswordPrompt: `[Pp]assword: ?$`,
+		ReadyPrompt:    `[^\r\n]*[$#>] ?$`, // 마지막 줄 전체를 프롬프트로 간주
+	},
+	{
+		Name:        "NoLogin",
+		ReadyPrompt: `[^\r\n]*> ?$`,
+	},
+}
+
+// TelnetProfiles는 기본 제공 프로필 목록을 반환 (UI 선택용, 비밀번호는 제외)
+func TelnetProfiles() []TelnetProfile {
+	profiles := make([]TelnetProfile, len(builtinTelnetProfiles))
+	for i, p := range builtinTelnetProfiles {
+		p.Password = ""
+		profiles[i] = p
+	}
+	return profiles
+}
+
+// resolve는 Name에 해당하는 기본 프로필 위에 비어 있지 않은 값을 덮어씀
+func (p TelnetProfile) resolve() (TelnetProfile, error) {
+	name := p.Name
+	if name == "" {
+		name = "GPL"
+	}
+	base := TelnetProfile{Name: name}
+	found := false
+	for _, builtin := range builtinTelnetProfiles {
+		if builtin.Name == name {
+			base, found = builtin, true
+			break
+		}
+	}
+	if !found && p.ReadyPrompt == "" {
+		return p, fmt.Errorf("알 수 없는 Telnet 프로필입니다: %s", p.Name)
+	}
+
+	overlay := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	overlay(&base.UserPrompt, p.UserPrompt)
+	overlay(&base.Username, p.Username)
+	overlay(&base.PasswordPrompt, p.PasswordPrompt)
+	overlay(&base.Password, p.Password)
+	overlay(&base.ReadyPrompt, p.ReadyPrompt)
+	overlay(&base.ResponseEnd, p.ResponseEnd)
+	base.Streaming = base.Streaming || p.Streaming
+	if p.LoginTimeoutMs > 0 {
+		base.LoginTimeoutMs = p.LoginTimeoutMs
+	}
+	if base.LoginTimeoutMs <= 0 {
+		base.LoginTimeoutMs = 2000
+	}
+	if base.ResponseEnd == "" {
+		base.ResponseEnd = base.ReadyPrompt
+	}
+	return base, nil
+}
+
+// telnetPrompts는 컴파일된 프로필 정규식
+type telnetPrompts struct {
+	user, password, ready, responseEnd *regexp.Regexp
+	loginTimeout                       time.Duration
+}
+
+func (p TelnetProfile) compile() (*telnetPrompts, error) {
+	compile := func(field, expr string) (*regexp.Regexp, error) {
+		if expr == "" {
+			return nil, nil
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("Telnet 프로필 %s 정규식 오류: %v", field, err)
+		}
+		return re, nil
+	}
+
+	prompts := &telnetPrompts{loginTimeout: time.Duration(p.LoginTimeoutMs) * time.Millisecond}
+	var err error
+	if prompts.user, err = compile("UserPrompt", p.UserPrompt); err != nil {
+		return nil, err
+	}
+	if prompts.password, err = compile("PasswordPrompt", p.PasswordPrompt); err != nil {
+		return nil, err
+	}
+	if prompts.ready, err = compile("ReadyPrompt", p.ReadyPrompt); err != nil {
+		return nil, err
+	}
+	if prompts.responseEnd, err = compile("ResponseEnd", p.ResponseEnd); err != nil {
+		return nil, err
+	}
+	if prompts.ready == nil {
+		return nil, fmt.Errorf("Telnet 프로필 %s 에 ReadyPrompt가 없습니다", p.Name)
+	}
+	return prompts, nil
+}
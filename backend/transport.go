@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Transport는 Serial, TCP, Telnet 등 하나의 물리 연결을 추상화
+type Transport interface {
+	// Open은 연결을 수립하고 수신 이벤트를 events로 전달하기 시작
+	Open(events TransportEvents) error
+	// Close는 사용자 요청에 의한 연결 해제 (OnClosed는 호출되지 않음)
+	Close() error
+	// Write는 데이터를 그대로 전송
+	Write(data []byte) error
+}
+
+// TransportEvents는 전송 계층이 세션 매니저로 올려보내는 콜백 묶음
+type TransportEvents struct {
+	OnReceive func(data []byte)
+	// OnClosed는 상대방 종료나 오류로 연결이 끊어졌을 때 한 번 호출 (정상 종료면 err == nil)
+	OnClosed func(err error)
+	// OnAccept는 리슨 방식 전송에서 새 클라이언트가 접속했을 때 호출
+	// 반환된 events로 자식 연결(child, 이미 연결된 상태)의 이벤트를 전달
+	OnAccept func(child Transport, peer string) TransportEvents
+	// OnDatagram은 데이터그램 방식 전송에서 메시지 하나를 송신 주소와 함께 전달 (프레이밍 없이 그대로 한 메시지)
+	OnDatagram func(data []byte, from string)
+	// OnInfo는 전송 계층의 알림 (SECS 시간 초과, 재시도 등)을 세션 로그로 전달
+	OnInfo func(message string)
+}
+
+// SessionConfig는 세션을 여는 데 필요한 설정
+type SessionConfig struct {
+	Type    string           `json:"type"`    // "serial", "tcp", "telnet", "udp" ...
+	Address string           `json:"address"` // 포트 이름 또는 "ip:port"
+	Serial  SerialLineConfig `json:"serial"`
+	Telnet  TelnetProfile    `json:"telnet"` // 비어 있으면 GPL 프로필
+	UDP     UDPConfig        `json:"udp"`
+	SECS1   SECS1Config      `json:"secs1"`
+	HSMS    HSMSConfig       `json:"hsms"`
+	// TxTerminator는 전송 시 붙이는 종료 문자 ("CRLF", "CR", "LF", "ETX", "NONE" 또는 "\x03" 형태, 빈 값 == CRLF)
+	TxTerminator string          `json:"txTerminator"`
+	Framing      FramingConfig   `json:"framing"` // 비어 있으면 전송 방식별 기본값
+	Reconnect    ReconnectPolicy `json:"reconnect"`
+	// Binary가 true면 전송 문자열을 16진수/이스케이프로 해석하고 수신을 원본 바이트로 전달 (TxTerminator 기본값 없음)
+	Binary bool `json:"binary"`
+	// ModemLines는 마지막으로 요청한 시리얼 DTR/RTS 출력 (nil == 둘 다 ON, 재연결 시 다시 적용)
+	ModemLines *ModemLines `json:"modemLines,omitempty"`
+}
+
+// TransportFactory는 설정으로부터 Transport를 생성
+type TransportFactory func(cfg SessionConfig) (Transport, error)
+
+var (
+	transportMu        sync.RWMutex
+	transportFactories = make(map[string]TransportFactory)
+)
+
+// RegisterTransport는 새 통신 방식을 등록 (각 구현 파일의 init에서 호출)
+func RegisterTransport(kind string, factory TransportFactory) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	transportFactories[kind] = factory
+}
+
+// TransportTypes는 등록된 통신 방식 목록을 반환
+func TransportTypes() []string {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	kinds := make([]string, 0, len(transportFactories))
+	for k := range transportFactories {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func newTransport(cfg SessionConfig) (Transport, error) {
+	transportMu.RLock()
+	factory, ok := transportFactories[cfg.Type]
+	transportMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("지원하지 않는 통신 방식입니다: %s", cfg.Type)
+	}
+	return factory(cfg)
+}
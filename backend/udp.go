@@ -0,0 +1,133 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+func init() {
+	RegisterTransport("udp", newUDPTransport)
+}
+
+// UDPConfig는 UDP 세션의 로컬 바인드와 수신 필터 설정
+type UDPConfig struct {
+	LocalAddress string `json:"localAddress"` // 바인드 주소 (":5000" 형태, 비어 있으면 임의 포트)
+	PeerOnly     bool   `json:"peerOnly"`     // true면 대상 주소에서 온 데이터그램만 수신
+}
+
+// udpTransport는 로컬 포트에 바인드하고 대상 주소로 데이터그램을 주고받음
+// 데이터그램 하나가 수신 메시지 하나 (OnDatagram으로 송신 주소와 함께 전달)
+type udpTransport struct {
+	addr    string
+	udp     UDPConfig
+	remote  *net.UDPAddr
+	conn    *net.UDPConn
+	closing bool
+	mu      sync.Mutex
+}
+
+func newUDPTransport(cfg SessionConfig) (Transport, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("UDP 대상 주소가 필요합니다")
+	}
+	return &udpTransport{addr: cfg.Address, udp: cfg.UDP}, nil
+}
+
+func (t *udpTransport) Open(events TransportEvents) error {
+	remote, err := net.ResolveUDPAddr("udp", t.addr)
+	if err != nil {
+		return fmt.Errorf("%s 주소 해석 실패: %v", t.addr, err)
+	}
+	local, err := net.ResolveUDPAddr("udp", t.udp.LocalAddress)
+	if err != nil {
+		return fmt.Errorf("%s 로컬 주소 해석 실패: %v", t.udp.LocalAddress, err)
+	}
+	conn, err := net.ListenUDP("udp", local)
+	if err != nil {
+		return fmt.Errorf("%s UDP 바인드 실패: %v", t.udp.LocalAddress, err)
+	}
+
+	t.mu.Lock()
+	t.remote = remote
+	t.conn = conn
+	t.closing = false
+	t.mu.Unlock()
+	fmt.Printf("%s 에 바인드했습니다. (대상 %s)\n", conn.LocalAddr(), t.addr)
+
+	go t.startReading(conn, remote, events)
+	return nil
+}
+
+// DefaultFraming은 데이터그램 경계를 그대로 쓰므로 프레이밍 없음
+func (t *udpTransport) DefaultFraming() FramingConfig {
+	return FramingConfig{Mode: "none"}
+}
+
+func (t *udpTransport) Close() error {
+	t.mu.Lock()
+	conn := t.conn
+	if conn == nil || t.closing {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closing = true
+	t.mu.Unlock()
+
+	err := conn.Close()
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		return fmt.Errorf("%s UDP 해제 실패: %v", t.addr, err)
+	}
+	fmt.Printf("%s UDP 소켓을 닫았습니다.\n", t.addr)
+	return nil
+}
+
+func (t *udpTransport) Write(data []byte) error {
+	t.mu.Lock()
+	conn, remote, closed := t.conn, t.remote, t.closing
+	t.mu.Unlock()
+	if conn == nil || closed {
+		return fmt.Errorf("%s 는 열려 있지 않습니다", t.addr)
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.WriteToUDP(data, remote); err != nil {
+		return fmt.Errorf("%s 데이터 전송 실패: %v", t.addr, err)
+	}
+	fmt.Printf("[%s] 데이터 전송: %q\n", t.addr, data)
+	return nil
+}
+
+func (t *udpTransport) startReading(conn *net.UDPConn, remote *net.UDPAddr, events TransportEvents) {
+	buff := make([]byte, 65535)
+	for {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		bytesRead, from, err := conn.ReadFromUDP(buff)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue // 타임아웃이면 재시도
+			}
+
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			// UDP는 연결이 없으므로 ICMP 포트 도달 불가(Windows WSAECONNRESET 등)도 세션을 닫지 않고 계속 수신
+			fmt.Printf("[%s] 데이터 읽기 오류 (무시): %v\n", t.addr, err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
+		if t.udp.PeerOnly && !(from.IP.Equal(remote.IP) && from.Port == remote.Port) {
+			continue
+		}
+		received := make([]byte, bytesRead)
+		copy(received, buff[:bytesRead])
+		if events.OnDatagram != nil {
+			events.OnDatagram(received, from.String())
+		} else {
+			events.OnReceive(received)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"ProtocolNexus/backend"
+	"ProtocolNexus/backend/EFEMTest"
 	"fmt"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"time"
@@ -13,7 +14,9 @@ func (a *App) LogPrint(printHandle, dataType, dataText string) {
 		"dataType": dataType,
 		"dataText": log,
 	})
-	backend.LoggingList[printHandle].Log(fmt.Sprintf("[%-4s] %s", dataType, log))
+	if logger, ok := backend.LoggingList[printHandle]; ok && logger != nil {
+		logger.Log(fmt.Sprintf("[%-4s] %s", dataType, log))
+	}
 }
 
 func (a *App) CommanderDisconn(connLineData ...string) {
@@ -25,3 +28,63 @@ func (a *App) CommanderDisconn(connLineData ...string) {
 		return
 	}
 }
+
+// commanderState는 재연결 상태 변화(RECONNECTING, RESTORED, GAVEUP)를 UI에 알림
+func (a *App) commanderState(sessionID, state string) {
+	runtime.EventsEmit(a.ctx, "commanderState", map[string]interface{}{
+		"sessionId": sessionID,
+		"state":     state,
+	})
+}
+
+// commanderRaw는 바이너리 모드 송수신 원본 바이트를 UI에 전달 (data는 base64로 직렬화됨)
+func (a *App) commanderRaw(sessionID, dataType string, data []byte) {
+	runtime.EventsEmit(a.ctx, "CommanderRaw", map[string]interface{}{
+		"sessionId": sessionID,
+		"dataType":  dataType,
+		"data":      data,
+	})
+}
+
+// scriptProgress는 스크립트 진행 상황을 UI에 전달
+func (a *App) scriptProgress(progress backend.ScriptProgress) {
+	runtime.EventsEmit(a.ctx, "scriptProgress", progress)
+}
+
+// cycleTestProgress는 사이클 테스트 진행 상황을 UI에 전달
+func (a *App) cycleTestProgress(progress EFEMTest.CycleProgress) {
+	runtime.EventsEmit(a.ctx, "cycleTest", progress)
+}
+
+// efemSystem은 선택한 EFEM 시스템 구성을 UI에 전달 (장비 패널 목록 갱신)
+func (a *App) efemSystem(cfg EFEMTest.SystemConfig) {
+	runtime.EventsEmit(a.ctx, "efemSystem", cfg)
+}
+
+// loadportStatus는 로드포트 상태 변화를 UI에 전달
+func (a *App) loadportStatus(status EFEMTest.LoadportStatus) {
+	runtime.EventsEmit(a.ctx, "loadportStatus", status)
+}
+
+// loadportMap은 매핑 결과를 UI에 전달, 기대 맵과 다르면 로그에도 남김
+func (a *App) loadportMap(result EFEMTest.SlotMapResult) {
+	if !result.Matched {
+		a.LogPrint("EFEMTestLog", "ERRO", fmt.Sprintf("[%s] 매핑 결과 불일치 %d슬롯", result.Map.Name, len(result.Mismatches)))
+	}
+	runtime.EventsEmit(a.ctx, "loadportMap", result)
+}
+
+// alignerStatus는 얼라이너 상태 변화를 UI에 전달
+func (a *App) alignerStatus(status EFEMTest.AlignerStatus) {
+	runtime.EventsEmit(a.ctx, "alignerStatus", status)
+}
+
+// wtrStatus는 로봇 상태 변화를 UI에 전달
+func (a *App) wtrStatus(status EFEMTest.WTRStatus) {
+	runtime.EventsEmit(a.ctx, "wtrStatus", status)
+}
+
+// wtrLog는 로봇별 동작 로그를 UI에 전달
+func (a *App) wtrLog(log EFEMTest.WTRLog) {
+	runtime.EventsEmit(a.ctx, "wtrLog", log)
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"ProtocolNexus/backend"
+	"ProtocolNexus/backend/EFEMTest"
+	"ProtocolNexus/backend/Modbus"
+	"ProtocolNexus/backend/Simulator"
 	"fmt"
 	"net"
 	"os"
@@ -9,79 +12,39 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
-var connTypeList = make(map[string]int)
-var connTypeMu sync.Mutex
-
-// connType {1 == Serial, 2 == TCP, 3 == Telnet}
-func (a *App) SendData(address, address2, data string) {
-	if net.ParseIP(address) != nil {
-		address = fmt.Sprintf("%s:%s", address, address2)
-	}
-
-	connTypeMu.Lock()
-	connType, ok := connTypeList[address]
-	connTypeMu.Unlock()
-
-	if !ok {
-		fmt.Println("Connection type not found for:", address)
-		return
-	}
-
-	var err error
-	switch connType {
-	case 1:
-		err = backend.SerialSendData(address, data)
-	case 2:
-		err = backend.TCPSendData(address, data)
-	case 3:
-		telData, err := backend.TelnetSendData(address, data)
-		if err != nil {
-			a.LogPrint("CommanderLog", "ERRO", err.Error())
-			a.CommanderDisconn()
-			return
-		}
-
-		a.LogPrint("CommanderLog", "SENT", data)
-		a.LogPrint("CommanderLog", "RECV", telData)
-		return
-	default:
-		fmt.Println("Unsupported connection type:", connType)
-		return
+// SendData는 세션 ID로 데이터를 전송 (SENT 로그는 세션 이벤트로 출력)
+func (a *App) SendData(sessionID, data string) {
+	if err := backend.SessionSend(sessionID, data); err != nil {
+		a.LogPrint("CommanderLog", "ERRO", err.Error())
 	}
+}
 
+// SendAndExpect는 데이터를 전송하고 expectPattern(정규식)과 일치하는 응답을 기다려 반환
+func (a *App) SendAndExpect(sessionID, data, expectPattern string, timeoutMs int) (backend.ExpectResult, error) {
+	result, err := backend.SendAndExpect(sessionID, data, expectPattern, time.Duration(timeoutMs)*time.Millisecond)
 	if err != nil {
 		a.LogPrint("CommanderLog", "ERRO", err.Error())
-	} else {
-		a.LogPrint("CommanderLog", "SENT", data)
 	}
+	return result, err
 }
 
 func (a *App) SerialList() []string {
 	return backend.FindSerialPort()
 }
 
+// TelnetProfiles는 선택 가능한 Telnet 프로필 목록을 반환
+func (a *App) TelnetProfiles() []backend.TelnetProfile {
+	return backend.TelnetProfiles()
+}
+
 func (a *App) SetPage(page string) {
-	connTypeMu.Lock()
-	defer connTypeMu.Unlock()
-	var err error
-	for k, v := range connTypeList {
-		fmt.Println(k, v)
-		switch v {
-		case 1:
-			err = backend.SerialDisconnect(k)
-		case 2:
-			err = backend.TCPDisconnect(k)
-		case 3:
-			err = backend.TelnetDisconnect(k)
-		}
-		delete(connTypeList, k)
-		if err != nil {
-			fmt.Println(err.Error())
-		}
+	for _, err := range backend.CloseAllSessions() {
+		fmt.Println(err.Error())
 	}
 	switch page {
 	case "Commander":
@@ -124,71 +87,136 @@ func (a *App) LogFolderOpen(w string) {
 	return
 }
 
-// connType = {-1 == Serial Disconnect, -2 == TCP Disconnect, 1 == Serial, 2 == TCP}
-func (a *App) CommanderConn(connType int, address, address2 string) bool {
-	ct := connType
-	if ct < 0 {
-		ct = -ct
-	}
-	if ct > 1 {
-		address = fmt.Sprintf("%s:%s", address, address2)
-	}
-	var err error
-	switch connType {
-	case -1:
-		err = backend.SerialDisconnect(address)
-	case -2:
-		err = backend.TCPDisconnect(address)
-	case -3:
-		err = backend.TelnetDisconnect(address)
-	case 1:
-		aNum, ok := strconv.Atoi(address2)
-		if ok != nil {
-			return false
-		}
-		dataHandler := func(port, dataType, data string) {
-			a.LogPrint("CommanderLog", dataType, data)
-			if dataType != "RECV" {
-				a.CommanderDisconn()
-			}
-		}
-		err = backend.SerialConnect(address, aNum, dataHandler)
-		if err != nil {
-			return false
-		}
-	case 2:
-		dataHandler := func(addr, dataType, data string) {
-			a.LogPrint("CommanderLog", dataType, data)
-			if dataType != "RECV" {
-				a.CommanderDisconn()
-			}
-		}
-		err = backend.TCPConnect(address, dataHandler)
-		if err != nil {
-			return false
-		}
-	case 3:
-		err = backend.TelnetConnect(address)
+// CommanderConn은 세션을 열고 세션 ID를 반환 (실패 시 빈 문자열)
+// connType == backend.TransportTypes() 중 하나, TCP 계열은 address:address2로 연결
+func (a *App) CommanderConn(connType, address, address2 string) string {
+	cfg := backend.SessionConfig{Type: connType, Address: address}
+	if connType == "serial" {
+		baudRate, err := strconv.Atoi(address2)
 		if err != nil {
-			return false
+			a.LogPrint("CommanderLog", "ERRO", fmt.Sprintf("잘못된 Baud Rate 입니다: %s", address2))
+			return ""
 		}
+		cfg.Serial = backend.DefaultSerialLineConfig(baudRate)
+	} else {
+		cfg.Address = net.JoinHostPort(address, address2)
+	}
+
+	sessionID, err := backend.OpenSession(cfg, a.commanderEvent)
+	if err != nil {
+		a.LogPrint("CommanderLog", "ERRO", err.Error())
+		return ""
+	}
+	a.LogPrint("CommanderLog", "INFO", fmt.Sprintf("%s Connected", cfg.Address))
+	return sessionID
+}
+
+// CommanderOpen은 종료 문자, 수신 프레이밍 등 전체 세션 설정으로 세션을 열고 세션 ID를 반환
+// 바이너리 모드면 원본 바이트를 CommanderRaw 이벤트로도 전달
+func (a *App) CommanderOpen(cfg backend.SessionConfig) string {
+	sessionID, err := backend.OpenSessionRaw(cfg, a.commanderEvent, a.commanderRaw)
+	if err != nil {
+		a.LogPrint("CommanderLog", "ERRO", err.Error())
+		return ""
+	}
+	a.LogPrint("CommanderLog", "INFO", fmt.Sprintf("%s Connected", cfg.Address))
+	return sessionID
+}
+
+// SerialConnect는 전체 회선 설정(Data Bits, Parity, Stop Bits, 흐름 제어)으로 시리얼 세션을 열고 세션 ID를 반환
+func (a *App) SerialConnect(portName string, line backend.SerialLineConfig) string {
+	sessionID, err := backend.SerialConnect(portName, line, a.commanderEvent)
+	if err != nil {
+		a.LogPrint("CommanderLog", "ERRO", err.Error())
+		return ""
+	}
+	a.LogPrint("CommanderLog", "INFO", fmt.Sprintf("%s Connected (%s)", portName, line))
+	return sessionID
+}
+
+// RemoteSerialConnect는 RFC 2217 터미널 서버(host:port)의 원격 시리얼 포트로 세션을 열고 세션 ID를 반환
+func (a *App) RemoteSerialConnect(address string, line backend.SerialLineConfig) string {
+	sessionID, err := backend.RemoteSerialConnect(address, line, a.commanderEvent)
+	if err != nil {
+		a.LogPrint("CommanderLog", "ERRO", err.Error())
+		return ""
+	}
+	a.LogPrint("CommanderLog", "INFO", fmt.Sprintf("%s Connected (%s)", address, line))
+	return sessionID
+}
+
+// CommanderSetLine은 연결된 시리얼(로컬/원격) 세션의 회선 설정을 바꿈
+func (a *App) CommanderSetLine(sessionID string, line backend.SerialLineConfig) error {
+	if err := backend.SessionSetLine(sessionID, line); err != nil {
+		a.LogPrint("CommanderLog", "ERRO", err.Error())
+		return err
+	}
+	return nil
+}
+
+// CommanderSetModemLines는 연결된 시리얼(로컬/원격) 세션의 DTR/RTS 출력을 바꿈
+func (a *App) CommanderSetModemLines(sessionID string, dtr, rts bool) error {
+	if err := backend.SessionSetModemLines(sessionID, dtr, rts); err != nil {
+		a.LogPrint("CommanderLog", "ERRO", err.Error())
+		return err
+	}
+	return nil
+}
+
+// CommanderClose는 사용자 요청으로 세션을 닫음
+func (a *App) CommanderClose(sessionID string) error {
+	if err := backend.CloseSession(sessionID); err != nil {
+		a.LogPrint("CommanderLog", "ERRO", err.Error())
+		return err
+	}
+	a.LogPrint("CommanderLog", "INFO", fmt.Sprintf("%s Disconnected", sessionID))
+	return nil
+}
+
+// commanderEvent는 세션 이벤트를 Commander 로그로 출력
+func (a *App) commanderEvent(sessionID, dataType, data string) {
+	switch dataType {
+	case "CLOSE":
+		a.LogPrint("CommanderLog", "INFO", data)
+		a.CommanderDisconn()
+	case "RECONNECTING", "RESTORED", "GAVEUP":
+		a.LogPrint("CommanderLog", "INFO", data)
+		a.commanderState(sessionID, dataType)
 	default:
-		return false
+		a.LogPrint("CommanderLog", dataType, data)
 	}
+}
+
+// ScriptRun은 Commander 스크립트를 sessionID 세션을 대상으로 실행하고 실행 ID를 반환
+// 진행 상황은 scriptProgress 이벤트로, log/실패는 CommanderLog로 출력
+func (a *App) ScriptRun(source, sessionID string) (string, error) {
+	runID, err := backend.ScriptRun(source, sessionID, a.scriptEvent)
 	if err != nil {
 		a.LogPrint("CommanderLog", "ERRO", err.Error())
 	}
-	connTypeMu.Lock()
-	defer connTypeMu.Unlock()
-	if connType > 0 {
-		a.LogPrint("CommanderLog", "INFO", fmt.Sprintf("%s Connected", address))
-		connTypeList[address] = connType
-		return true
-	} else {
-		a.LogPrint("CommanderLog", "INFO", fmt.Sprintf("%s Disconnected", address))
-		delete(connTypeList, address)
-		return false
+	return runID, err
+}
+
+func (a *App) ScriptPause(runID string) error {
+	return backend.ScriptPause(runID)
+}
+
+func (a *App) ScriptResume(runID string) error {
+	return backend.ScriptResume(runID)
+}
+
+func (a *App) ScriptStop(runID string) error {
+	return backend.ScriptStop(runID)
+}
+
+func (a *App) scriptEvent(progress backend.ScriptProgress) {
+	switch progress.State {
+	case "LOG", "START", "DONE", "STOPPED":
+		a.LogPrint("CommanderLog", "INFO", fmt.Sprintf("[%s] %s", progress.RunID, progress.Message))
+	case "FAILED":
+		a.LogPrint("CommanderLog", "ERRO", fmt.Sprintf("[%s] %s", progress.RunID, progress.Message))
 	}
+	a.scriptProgress(progress)
 }
 
 func (a *App) CommanderIsLogging(logging bool) error {
@@ -203,3 +231,408 @@ func (a *App) CommanderIsLogging(logging bool) error {
 	}
 	return nil
 }
+
+// EFEMTestIsLogging은 EFEM 장비/사이클 테스트 로그(EFEMTestLog)의 파일 기록을 켜고 끔
+func (a *App) EFEMTestIsLogging(logging bool) error {
+	if logging {
+		backend.LoggingList["EFEMTestLog"] = backend.NewLogger(filepath.Join(backend.ProgramFolderPath, "EFEM Test", "LOG", fmt.Sprintf("LOG-%s.txt", time.Now().Format("060102"))))
+		if backend.LoggingList["EFEMTestLog"] == nil {
+			delete(backend.LoggingList, "EFEMTestLog")
+			return fmt.Errorf("로그 기록 실패")
+		}
+	} else if logger, ok := backend.LoggingList["EFEMTestLog"]; ok {
+		logger.Close()
+	}
+	return nil
+}
+
+// EFEMConnect는 EFEM 장비(LP1, ALG1, WTR1 ...) 세션을 열고 이름으로 등록 (실패 시 false)
+// LP/ALG/WTR로 시작하면 장비 드라이버로 연결하고 상태 변화를 loadportStatus/alignerStatus/wtrStatus 이벤트로 전달
+func (a *App) EFEMConnect(name string, cfg backend.SessionConfig) bool {
+	onEvent := func(sessionID, dataType, data string) {
+		switch dataType {
+		case "CLOSE", "RECONNECTING", "RESTORED", "GAVEUP":
+			dataType = "INFO"
+		}
+		a.LogPrint("EFEMTestLog", dataType, fmt.Sprintf("[%s] %s", name, data))
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(name, "LP"):
+		_, err = EFEMTest.ConnectLoadport(name, cfg, onEvent, a.loadportStatus, a.loadportMap)
+	case strings.HasPrefix(name, "ALG"):
+		_, err = EFEMTest.ConnectAligner(name, cfg, onEvent, a.alignerStatus)
+	case strings.HasPrefix(name, "WTR"):
+		_, err = EFEMTest.ConnectWTR(name, cfg, onEvent, a.wtrStatus, a.wtrLog)
+	default:
+		_, err = EFEMTest.ConnectDevice(name, cfg, onEvent)
+	}
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+		return false
+	}
+	a.LogPrint("EFEMTestLog", "INFO", fmt.Sprintf("[%s] %s Connected", name, cfg.Address))
+	return true
+}
+
+func (a *App) EFEMDisconnect(name string) error {
+	switch {
+	case strings.HasPrefix(name, "LP"):
+		return EFEMTest.DisconnectLoadport(name)
+	case strings.HasPrefix(name, "ALG"):
+		return EFEMTest.DisconnectAligner(name)
+	case strings.HasPrefix(name, "WTR"):
+		return EFEMTest.DisconnectWTR(name)
+	}
+	return EFEMTest.DisconnectDevice(name)
+}
+
+// LoadportCommand는 로드포트 명령 (command == "Load", "Unload", "Origin", "Reset")
+// 완료(INF)까지 기다리고 거부/실패는 오류로 반환
+func (a *App) LoadportCommand(name, command string) error {
+	lp, err := EFEMTest.LoadportByName(name)
+	if err == nil {
+		switch command {
+		case "Load":
+			err = lp.Load()
+		case "Unload":
+			err = lp.Unload()
+		case "Origin":
+			err = lp.Origin()
+		case "Reset":
+			err = lp.ResetError()
+		default:
+			err = fmt.Errorf("지원하지 않는 로드포트 명령입니다: %s", command)
+		}
+	}
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+		return err
+	}
+	a.LogPrint("EFEMTestLog", "INFO", fmt.Sprintf("[%s] %s 완료", name, command))
+	return nil
+}
+
+// LoadportStatus는 로드포트 상태를 즉시 읽음
+func (a *App) LoadportStatus(name string) (EFEMTest.LoadportStatus, error) {
+	lp, err := EFEMTest.LoadportByName(name)
+	if err != nil {
+		return EFEMTest.LoadportStatus{Name: name}, err
+	}
+	return lp.ReadStatus()
+}
+
+// LoadportMap은 매핑 결과를 읽음, expected가 있으면 기대 맵으로 설정하고 비교
+// 결과와 불일치는 loadportMap 이벤트로도 전달
+func (a *App) LoadportMap(name, expected string) (EFEMTest.SlotMapResult, error) {
+	lp, err := EFEMTest.LoadportByName(name)
+	if err == nil {
+		err = lp.SetExpectedMap(expected)
+	}
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+		return EFEMTest.SlotMapResult{}, err
+	}
+	return lp.ReadMap()
+}
+
+// EFEMSystemList는 System 폴더의 EFEM 시스템 목록
+func (a *App) EFEMSystemList() ([]string, error) {
+	return EFEMTest.ListSystems()
+}
+
+func (a *App) EFEMSystemLoad(name string) (EFEMTest.SystemConfig, error) {
+	return EFEMTest.LoadSystem(name)
+}
+
+func (a *App) EFEMSystemSave(cfg EFEMTest.SystemConfig) error {
+	return EFEMTest.SaveSystem(cfg)
+}
+
+func (a *App) EFEMSystemCreate(name string) (EFEMTest.SystemConfig, error) {
+	return EFEMTest.CreateSystem(name)
+}
+
+// EFEMSystemConnect는 시스템 설정의 장비를 모두 연결 (장비 이름 -> 성공 여부)
+// 장비 목록은 efemSystem 이벤트로 먼저 전달하고 로봇에는 스테이션 테이블을 적용
+func (a *App) EFEMSystemConnect(name string) (map[string]bool, error) {
+	cfg, err := EFEMTest.LoadSystem(name)
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+		return nil, err
+	}
+	EFEMTest.SetActiveSystem(cfg)
+	a.efemSystem(cfg)
+
+	var devices []EFEMTest.DeviceConfig
+	devices = append(devices, cfg.Loadports...)
+	devices = append(devices, cfg.Aligners...)
+	for _, r := range cfg.Robots {
+		devices = append(devices, EFEMTest.DeviceConfig{Name: r.Name, Connection: r.Connection})
+	}
+
+	results := make(map[string]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, d := range devices {
+		wg.Add(1)
+		go func(d EFEMTest.DeviceConfig) {
+			defer wg.Done()
+			connected := a.EFEMConnect(d.Name, d.Connection)
+			mu.Lock()
+			results[d.Name] = connected
+			mu.Unlock()
+		}(d)
+	}
+	wg.Wait()
+
+	for _, r := range cfg.Robots {
+		if robot, err := EFEMTest.WTRByName(r.Name); err == nil && len(r.Stations) > 0 {
+			robot.SetStations(r.Stations)
+		}
+	}
+	return results, nil
+}
+
+// CycleSequenceLoad는 선택한 시스템에 허용된 시퀀스 하나를 이름으로 읽음
+func (a *App) CycleSequenceLoad(name string) (EFEMTest.CycleSequence, error) {
+	return EFEMTest.LoadSequence(name)
+}
+
+func (a *App) CycleSequenceSave(seq EFEMTest.CycleSequence) error {
+	return EFEMTest.SaveSequence(seq)
+}
+
+// CycleTestStart는 시퀀스를 repeat번 실행, 진행 상황은 cycleTest 이벤트로 전달
+func (a *App) CycleTestStart(seq EFEMTest.CycleSequence, repeat int) error {
+	err := EFEMTest.CycleTestStart(seq, repeat, a.cycleEvent)
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+	}
+	return err
+}
+
+func (a *App) CycleTestStop() error {
+	return EFEMTest.CycleTestStop()
+}
+
+// CycleTestEMO는 사이클 테스트를 즉시 중단하고 장비에 EMO 명령을 전송
+func (a *App) CycleTestEMO() {
+	for _, err := range EFEMTest.CycleTestEMO() {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+	}
+}
+
+func (a *App) CycleTestResults() []EFEMTest.CycleResult {
+	return EFEMTest.CycleTestResults()
+}
+
+func (a *App) cycleEvent(progress EFEMTest.CycleProgress) {
+	switch progress.State {
+	case "CYCLE":
+		dataType := "INFO"
+		if !progress.Result.Passed {
+			dataType = "ERRO"
+		}
+		a.LogPrint("EFEMTestLog", dataType, fmt.Sprintf("Cycle %d/%d %s (%dms)", progress.Cycle, progress.Repeat, progress.Message, progress.Result.ElapsedMs))
+	case "START", "DONE", "STOPPED", "ABORTED":
+		a.LogPrint("EFEMTestLog", "INFO", progress.Message)
+	}
+	a.cycleTestProgress(progress)
+}
+
+// AlignerCommand는 얼라이너 명령 (command == "Align", "Reset", "VacOn", "VacOff")
+// Align은 노치를 angle(deg)로 맞추고 노치 각도와 오프셋을 반환
+func (a *App) AlignerCommand(name, command string, angle float64) (EFEMTest.AlignResult, error) {
+	var result EFEMTest.AlignResult
+	alg, err := EFEMTest.AlignerByName(name)
+	if err == nil {
+		switch command {
+		case "Align":
+			result, err = alg.Align(angle)
+		case "Reset":
+			err = alg.Reset()
+		case "VacOn":
+			err = alg.VacuumOn()
+		case "VacOff":
+			err = alg.VacuumOff()
+		default:
+			err = fmt.Errorf("지원하지 않는 얼라이너 명령입니다: %s", command)
+		}
+	}
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+		return result, err
+	}
+	if command == "Align" {
+		a.LogPrint("EFEMTestLog", "INFO", fmt.Sprintf("[%s] Align 완료 (Notch %.2f, X %.3f, Y %.3f)", name, result.Notch, result.OffsetX, result.OffsetY))
+	} else {
+		a.LogPrint("EFEMTestLog", "INFO", fmt.Sprintf("[%s] %s 완료", name, command))
+	}
+	return result, nil
+}
+
+// AlignerStatus는 얼라이너 상태를 즉시 읽음
+func (a *App) AlignerStatus(name string) (EFEMTest.AlignerStatus, error) {
+	alg, err := EFEMTest.AlignerByName(name)
+	if err != nil {
+		return EFEMTest.AlignerStatus{Name: name}, err
+	}
+	return alg.ReadStatus()
+}
+
+// WTRCommand는 로봇 명령 (command == "Get", "Put", "Origin", "Reset")
+// Get/Put은 stage, slot, hand를 스테이션 테이블로 확인하고 완료까지 기다림, 진행은 wtrLog 이벤트로 전달
+func (a *App) WTRCommand(name, command, stage string, slot int, hand string) error {
+	robot, err := EFEMTest.WTRByName(name)
+	if err == nil {
+		switch command {
+		case "Get":
+			err = robot.Get(stage, slot, hand)
+		case "Put":
+			err = robot.Put(stage, slot, hand)
+		case "Origin":
+			err = robot.Origin()
+		case "Reset":
+			err = robot.ResetError()
+		default:
+			err = fmt.Errorf("지원하지 않는 로봇 명령입니다: %s", command)
+		}
+	}
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+		return err
+	}
+	a.LogPrint("EFEMTestLog", "INFO", fmt.Sprintf("[%s] %s 완료", name, command))
+	return nil
+}
+
+// WTRStatus는 로봇 상태를 즉시 읽음
+func (a *App) WTRStatus(name string) (EFEMTest.WTRStatus, error) {
+	robot, err := EFEMTest.WTRByName(name)
+	if err != nil {
+		return EFEMTest.WTRStatus{Name: name}, err
+	}
+	return robot.ReadStatus()
+}
+
+// WTRLogs는 로봇별로 보관 중인 동작 로그 (화면을 다시 열 때 복원용)
+func (a *App) WTRLogs(name string) ([]EFEMTest.WTRLog, error) {
+	robot, err := EFEMTest.WTRByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return robot.Logs(), nil
+}
+
+// SimulatorStart는 가상 장비를 시작 (kind == "loadport", "aligner", "robot", "gpl")
+func (a *App) SimulatorStart(cfg Simulator.SimConfig) error {
+	err := Simulator.Start(cfg)
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+	}
+	return err
+}
+
+func (a *App) SimulatorStop(name string) error {
+	return Simulator.Stop(name)
+}
+
+// SimulatorUpdate는 실행 중인 가상 장비의 지연, 고장, 알람, 매핑 결과를 바꿈
+func (a *App) SimulatorUpdate(cfg Simulator.SimConfig) error {
+	return Simulator.Update(cfg)
+}
+
+func (a *App) SimulatorList() []Simulator.SimConfig {
+	return Simulator.List()
+}
+
+// SimulatorStartSystem은 EFEM 시스템 설정의 장비 주소마다 가상 장비를 시작 (장비 이름 -> 오류 메시지, 성공은 "")
+func (a *App) SimulatorStartSystem(system string) (map[string]string, error) {
+	cfg, err := EFEMTest.LoadSystem(system)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]string)
+	start := func(name, kind string, conn backend.SessionConfig) {
+		results[name] = ""
+		if conn.Type != "tcp" {
+			results[name] = fmt.Sprintf("%s 통신은 가상 장비를 지원하지 않습니다", conn.Type)
+			return
+		}
+		if err := a.SimulatorStart(Simulator.SimConfig{Name: name, Kind: kind, Address: conn.Address, DelayMs: 500}); err != nil {
+			results[name] = err.Error()
+		}
+	}
+	for _, d := range cfg.Loadports {
+		start(d.Name, "loadport", d.Connection)
+	}
+	for _, d := range cfg.Aligners {
+		start(d.Name, "aligner", d.Connection)
+	}
+	for _, r := range cfg.Robots {
+		start(r.Name, "robot", r.Connection)
+	}
+	return results, nil
+}
+
+// ModbusConnect는 I/O 모듈, FFU 컨트롤러 등 Modbus 장치(RTU 또는 TCP)에 마스터로 연결
+// 송수신 프레임은 16진수로 EFEMTestLog에 기록
+func (a *App) ModbusConnect(name string, cfg Modbus.Config) error {
+	onEvent := func(sessionID, dataType, data string) {
+		switch dataType {
+		case "CLOSE", "RECONNECTING", "RESTORED", "GAVEUP":
+			dataType = "INFO"
+		}
+		a.LogPrint("EFEMTestLog", dataType, fmt.Sprintf("[%s] %s", name, data))
+	}
+	if _, err := Modbus.Connect(name, cfg, onEvent); err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", err.Error())
+		return err
+	}
+	a.LogPrint("EFEMTestLog", "INFO", fmt.Sprintf("[%s] Modbus %s %s Connected", name, strings.ToUpper(cfg.Mode), cfg.Connection.Address))
+	return nil
+}
+
+func (a *App) ModbusDisconnect(name string) error {
+	return Modbus.Disconnect(name)
+}
+
+// ModbusRead는 코일, 디지털 입력, 입력/보유 레지스터를 읽음 (req.Table로 구분)
+func (a *App) ModbusRead(name string, req Modbus.Request) (Modbus.Result, error) {
+	master, err := Modbus.ByName(name)
+	var result Modbus.Result
+	if err == nil {
+		result, err = master.Read(req)
+	}
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", fmt.Sprintf("[%s] %s 읽기 실패 (유닛 %d, 주소 %d): %v", name, req.Table, req.Unit, req.Address, err))
+		return result, err
+	}
+	values := fmt.Sprint(result.Registers)
+	if result.Bits != nil {
+		values = fmt.Sprint(result.Bits)
+	}
+	a.LogPrint("EFEMTestLog", "INFO", fmt.Sprintf("[%s] %s 읽기 (유닛 %d, 주소 %d, %d개) %s", name, req.Table, req.Unit, req.Address, req.Count, values))
+	return result, nil
+}
+
+// ModbusWrite는 코일 또는 보유 레지스터에 씀 (값이 하나면 단일 쓰기)
+func (a *App) ModbusWrite(name string, req Modbus.Request) error {
+	master, err := Modbus.ByName(name)
+	if err == nil {
+		err = master.Write(req)
+	}
+	values := fmt.Sprint(req.Registers)
+	if req.Table == Modbus.TableCoil {
+		values = fmt.Sprint(req.Bits)
+	}
+	if err != nil {
+		a.LogPrint("EFEMTestLog", "ERRO", fmt.Sprintf("[%s] %s 쓰기 실패 (유닛 %d, 주소 %d): %v", name, req.Table, req.Unit, req.Address, err))
+		return err
+	}
+	a.LogPrint("EFEMTestLog", "INFO", fmt.Sprintf("[%s] %s 쓰기 (유닛 %d, 주소 %d) %s", name, req.Table, req.Unit, req.Address, values))
+	return nil
+}
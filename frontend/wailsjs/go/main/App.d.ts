@@ -1,20 +1,110 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {EFEMTest} from '../models';
+import {Modbus} from '../models';
+import {Simulator} from '../models';
+import {backend} from '../models';
 
-export function CommanderConn(arg1:number,arg2:string,arg3:string):Promise<boolean>;
+export function AlignerCommand(arg1:string,arg2:string,arg3:number):Promise<EFEMTest.AlignResult>;
+
+export function AlignerStatus(arg1:string):Promise<EFEMTest.AlignerStatus>;
+
+export function CommanderClose(arg1:string):Promise<void>;
+
+export function CommanderConn(arg1:string,arg2:string,arg3:string):Promise<string>;
 
 export function CommanderDisconn(arg1:Array<string>):Promise<void>;
 
 export function CommanderIsLogging(arg1:boolean):Promise<void>;
 
+export function CommanderOpen(arg1:backend.SessionConfig):Promise<string>;
+
+export function CommanderSetLine(arg1:string,arg2:backend.SerialLineConfig):Promise<void>;
+
+export function CommanderSetModemLines(arg1:string,arg2:boolean,arg3:boolean):Promise<void>;
+
+export function CycleSequenceLoad(arg1:string):Promise<EFEMTest.CycleSequence>;
+
+export function CycleSequenceSave(arg1:EFEMTest.CycleSequence):Promise<void>;
+
+export function CycleTestEMO():Promise<void>;
+
+export function CycleTestResults():Promise<Array<EFEMTest.CycleResult>>;
+
+export function CycleTestStart(arg1:EFEMTest.CycleSequence,arg2:number):Promise<void>;
+
+export function CycleTestStop():Promise<void>;
+
+export function EFEMConnect(arg1:string,arg2:backend.SessionConfig):Promise<boolean>;
+
+export function EFEMDisconnect(arg1:string):Promise<void>;
+
+export function EFEMSystemConnect(arg1:string):Promise<{[key: string]: boolean}>;
+
+export function EFEMSystemCreate(arg1:string):Promise<EFEMTest.SystemConfig>;
+
+export function EFEMSystemList():Promise<Array<string>>;
+
+export function EFEMSystemLoad(arg1:string):Promise<EFEMTest.SystemConfig>;
+
+export function EFEMSystemSave(arg1:EFEMTest.SystemConfig):Promise<void>;
+
+export function EFEMTestIsLogging(arg1:boolean):Promise<void>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function LoadportCommand(arg1:string,arg2:string):Promise<void>;
+
+export function LoadportMap(arg1:string,arg2:string):Promise<EFEMTest.SlotMapResult>;
+
+export function LoadportStatus(arg1:string):Promise<EFEMTest.LoadportStatus>;
+
 export function LogFolderOpen(arg1:string):Promise<void>;
 
 export function LogPrint(arg1:string,arg2:string,arg3:string):Promise<void>;
 
-export function SendData(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function ModbusConnect(arg1:string,arg2:Modbus.Config):Promise<void>;
+
+export function ModbusDisconnect(arg1:string):Promise<void>;
+
+export function ModbusRead(arg1:string,arg2:Modbus.Request):Promise<Modbus.Result>;
+
+export function ModbusWrite(arg1:string,arg2:Modbus.Request):Promise<void>;
+
+export function RemoteSerialConnect(arg1:string,arg2:backend.SerialLineConfig):Promise<string>;
+
+export function ScriptPause(arg1:string):Promise<void>;
+
+export function ScriptResume(arg1:string):Promise<void>;
+
+export function ScriptRun(arg1:string,arg2:string):Promise<string>;
+
+export function ScriptStop(arg1:string):Promise<void>;
+
+export function SendAndExpect(arg1:string,arg2:string,arg3:string,arg4:number):Promise<backend.ExpectResult>;
+
+export function SendData(arg1:string,arg2:string):Promise<void>;
+
+export function SerialConnect(arg1:string,arg2:backend.SerialLineConfig):Promise<string>;
 
 export function SerialList():Promise<Array<string>>;
 
 export function SetPage(arg1:string):Promise<void>;
+
+export function SimulatorList():Promise<Array<Simulator.SimConfig>>;
+
+export function SimulatorStart(arg1:Simulator.SimConfig):Promise<void>;
+
+export function SimulatorStartSystem(arg1:string):Promise<{[key: string]: string}>;
+
+export function SimulatorStop(arg1:string):Promise<void>;
+
+export function SimulatorUpdate(arg1:Simulator.SimConfig):Promise<void>;
+
+export function TelnetProfiles():Promise<Array<backend.TelnetProfile>>;
+
+export function WTRCommand(arg1:string,arg2:string,arg3:string,arg4:number,arg5:string):Promise<void>;
+
+export function WTRLogs(arg1:string):Promise<Array<EFEMTest.WTRLog>>;
+
+export function WTRStatus(arg1:string):Promise<EFEMTest.WTRStatus>;
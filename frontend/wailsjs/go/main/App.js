@@ -2,6 +2,18 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AlignerCommand(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AlignerCommand'](arg1, arg2, arg3);
+}
+
+export function AlignerStatus(arg1) {
+  return window['go']['main']['App']['AlignerStatus'](arg1);
+}
+
+export function CommanderClose(arg1) {
+  return window['go']['main']['App']['CommanderClose'](arg1);
+}
+
 export function CommanderConn(arg1, arg2, arg3) {
   return window['go']['main']['App']['CommanderConn'](arg1, arg2, arg3);
 }
@@ -14,10 +26,90 @@ export function CommanderIsLogging(arg1) {
   return window['go']['main']['App']['CommanderIsLogging'](arg1);
 }
 
+export function CommanderOpen(arg1) {
+  return window['go']['main']['App']['CommanderOpen'](arg1);
+}
+
+export function CommanderSetLine(arg1, arg2) {
+  return window['go']['main']['App']['CommanderSetLine'](arg1, arg2);
+}
+
+export function CommanderSetModemLines(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CommanderSetModemLines'](arg1, arg2, arg3);
+}
+
+export function CycleSequenceLoad(arg1) {
+  return window['go']['main']['App']['CycleSequenceLoad'](arg1);
+}
+
+export function CycleSequenceSave(arg1) {
+  return window['go']['main']['App']['CycleSequenceSave'](arg1);
+}
+
+export function CycleTestEMO() {
+  return window['go']['main']['App']['CycleTestEMO']();
+}
+
+export function CycleTestResults() {
+  return window['go']['main']['App']['CycleTestResults']();
+}
+
+export function CycleTestStart(arg1, arg2) {
+  return window['go']['main']['App']['CycleTestStart'](arg1, arg2);
+}
+
+export function CycleTestStop() {
+  return window['go']['main']['App']['CycleTestStop']();
+}
+
+export function EFEMConnect(arg1, arg2) {
+  return window['go']['main']['App']['EFEMConnect'](arg1, arg2);
+}
+
+export function EFEMDisconnect(arg1) {
+  return window['go']['main']['App']['EFEMDisconnect'](arg1);
+}
+
+export function EFEMSystemConnect(arg1) {
+  return window['go']['main']['App']['EFEMSystemConnect'](arg1);
+}
+
+export function EFEMSystemCreate(arg1) {
+  return window['go']['main']['App']['EFEMSystemCreate'](arg1);
+}
+
+export function EFEMSystemList() {
+  return window['go']['main']['App']['EFEMSystemList']();
+}
+
+export function EFEMSystemLoad(arg1) {
+  return window['go']['main']['App']['EFEMSystemLoad'](arg1);
+}
+
+export function EFEMSystemSave(arg1) {
+  return window['go']['main']['App']['EFEMSystemSave'](arg1);
+}
+
+export function EFEMTestIsLogging(arg1) {
+  return window['go']['main']['App']['EFEMTestIsLogging'](arg1);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function LoadportCommand(arg1, arg2) {
+  return window['go']['main']['App']['LoadportCommand'](arg1, arg2);
+}
+
+export function LoadportMap(arg1, arg2) {
+  return window['go']['main']['App']['LoadportMap'](arg1, arg2);
+}
+
+export function LoadportStatus(arg1) {
+  return window['go']['main']['App']['LoadportStatus'](arg1);
+}
+
 export function LogFolderOpen(arg1) {
   return window['go']['main']['App']['LogFolderOpen'](arg1);
 }
@@ -26,8 +118,52 @@ export function LogPrint(arg1, arg2, arg3) {
   return window['go']['main']['App']['LogPrint'](arg1, arg2, arg3);
 }
 
-export function SendData(arg1, arg2, arg3) {
-  return window['go']['main']['App']['SendData'](arg1, arg2, arg3);
+export function ModbusConnect(arg1, arg2) {
+  return window['go']['main']['App']['ModbusConnect'](arg1, arg2);
+}
+
+export function ModbusDisconnect(arg1) {
+  return window['go']['main']['App']['ModbusDisconnect'](arg1);
+}
+
+export function ModbusRead(arg1, arg2) {
+  return window['go']['main']['App']['ModbusRead'](arg1, arg2);
+}
+
+export function ModbusWrite(arg1, arg2) {
+  return window['go']['main']['App']['ModbusWrite'](arg1, arg2);
+}
+
+export function RemoteSerialConnect(arg1, arg2) {
+  return window['go']['main']['App']['RemoteSerialConnect'](arg1, arg2);
+}
+
+export function ScriptPause(arg1) {
+  return window['go']['main']['App']['ScriptPause'](arg1);
+}
+
+export function ScriptResume(arg1) {
+  return window['go']['main']['App']['ScriptResume'](arg1);
+}
+
+export function ScriptRun(arg1, arg2) {
+  return window['go']['main']['App']['ScriptRun'](arg1, arg2);
+}
+
+export function ScriptStop(arg1) {
+  return window['go']['main']['App']['ScriptStop'](arg1);
+}
+
+export function SendAndExpect(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SendAndExpect'](arg1, arg2, arg3, arg4);
+}
+
+export function SendData(arg1, arg2) {
+  return window['go']['main']['App']['SendData'](arg1, arg2);
+}
+
+export function SerialConnect(arg1, arg2) {
+  return window['go']['main']['App']['SerialConnect'](arg1, arg2);
 }
 
 export function SerialList() {
@@ -37,3 +173,39 @@ export function SerialList() {
 export function SetPage(arg1) {
   return window['go']['main']['App']['SetPage'](arg1);
 }
+
+export function SimulatorList() {
+  return window['go']['main']['App']['SimulatorList']();
+}
+
+export function SimulatorStart(arg1) {
+  return window['go']['main']['App']['SimulatorStart'](arg1);
+}
+
+export function SimulatorStartSystem(arg1) {
+  return window['go']['main']['App']['SimulatorStartSystem'](arg1);
+}
+
+export function SimulatorStop(arg1) {
+  return window['go']['main']['App']['SimulatorStop'](arg1);
+}
+
+export function SimulatorUpdate(arg1) {
+  return window['go']['main']['App']['SimulatorUpdate'](arg1);
+}
+
+export function TelnetProfiles() {
+  return window['go']['main']['App']['TelnetProfiles']();
+}
+
+export function WTRCommand(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['WTRCommand'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function WTRLogs(arg1) {
+  return window['go']['main']['App']['WTRLogs'](arg1);
+}
+
+export function WTRStatus(arg1) {
+  return window['go']['main']['App']['WTRStatus'](arg1);
+}
@@ -0,0 +1,747 @@
+export namespace EFEMTest {
+	
+	export class AlignResult {
+	    notch: number;
+	    offsetX: number;
+	    offsetY: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AlignResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.notch = source["notch"];
+	        this.offsetX = source["offsetX"];
+	        this.offsetY = source["offsetY"];
+	    }
+	}
+	export class AlignerStatus {
+	    name: string;
+	    connected: boolean;
+	    run: boolean;
+	    stop: boolean;
+	    origin: boolean;
+	    overrun: boolean;
+	    alarm: boolean;
+	    errorCode: string;
+	    wafer: boolean;
+	    vacuum: boolean;
+	    aligned: boolean;
+	    notch: number;
+	    offsetX: number;
+	    offsetY: number;
+	    raw: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AlignerStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.connected = source["connected"];
+	        this.run = source["run"];
+	        this.stop = source["stop"];
+	        this.origin = source["origin"];
+	        this.overrun = source["overrun"];
+	        this.alarm = source["alarm"];
+	        this.errorCode = source["errorCode"];
+	        this.wafer = source["wafer"];
+	        this.vacuum = source["vacuum"];
+	        this.aligned = source["aligned"];
+	        this.notch = source["notch"];
+	        this.offsetX = source["offsetX"];
+	        this.offsetY = source["offsetY"];
+	        this.raw = source["raw"];
+	    }
+	}
+	export class CycleResult {
+	    cycle: number;
+	    passed: boolean;
+	    failedStep: string;
+	    message: string;
+	    elapsedMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CycleResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cycle = source["cycle"];
+	        this.passed = source["passed"];
+	        this.failedStep = source["failedStep"];
+	        this.message = source["message"];
+	        this.elapsedMs = source["elapsedMs"];
+	    }
+	}
+	export class CycleSequence {
+	    name: string;
+	    system: string;
+	    steps: CycleStep[];
+	    stopOnFail: boolean;
+	    emoCommands: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new CycleSequence(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.system = source["system"];
+	        this.steps = this.convertValues(source["steps"], CycleStep);
+	        this.stopOnFail = source["stopOnFail"];
+	        this.emoCommands = source["emoCommands"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CycleStep {
+	    name: string;
+	    device: string;
+	    command: string;
+	    expect: string;
+	    timeoutMs: number;
+	    delayMs: number;
+	    expectMap: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CycleStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.device = source["device"];
+	        this.command = source["command"];
+	        this.expect = source["expect"];
+	        this.timeoutMs = source["timeoutMs"];
+	        this.delayMs = source["delayMs"];
+	        this.expectMap = source["expectMap"];
+	    }
+	}
+	export class DeviceConfig {
+	    name: string;
+	    connection: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeviceConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.connection = source["connection"];
+	    }
+	}
+	export class LoadportStatus {
+	    name: string;
+	    connected: boolean;
+	    equipment: string;
+	    run: boolean;
+	    stop: boolean;
+	    origin: boolean;
+	    overrun: boolean;
+	    alarm: boolean;
+	    errorCode: string;
+	    carrier: string;
+	    clamped: boolean;
+	    docked: boolean;
+	    doorOpen: boolean;
+	    mapped: boolean;
+	    raw: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoadportStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.connected = source["connected"];
+	        this.equipment = source["equipment"];
+	        this.run = source["run"];
+	        this.stop = source["stop"];
+	        this.origin = source["origin"];
+	        this.overrun = source["overrun"];
+	        this.alarm = source["alarm"];
+	        this.errorCode = source["errorCode"];
+	        this.carrier = source["carrier"];
+	        this.clamped = source["clamped"];
+	        this.docked = source["docked"];
+	        this.doorOpen = source["doorOpen"];
+	        this.mapped = source["mapped"];
+	        this.raw = source["raw"];
+	    }
+	}
+	export class RobotConfig {
+	    name: string;
+	    connection: any;
+	    stations: WTRStation[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RobotConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.connection = source["connection"];
+	        this.stations = this.convertValues(source["stations"], WTRStation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SlotMap {
+	    name: string;
+	    slots: string[];
+	    present: number;
+	    raw: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SlotMap(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.slots = source["slots"];
+	        this.present = source["present"];
+	        this.raw = source["raw"];
+	    }
+	}
+	export class SlotMapResult {
+	    map: SlotMap;
+	    expected: string;
+	    matched: boolean;
+	    mismatches: SlotMismatch[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SlotMapResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.map = this.convertValues(source["map"], SlotMap);
+	        this.expected = source["expected"];
+	        this.matched = source["matched"];
+	        this.mismatches = this.convertValues(source["mismatches"], SlotMismatch);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SlotMismatch {
+	    slot: number;
+	    expected: string;
+	    actual: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SlotMismatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slot = source["slot"];
+	        this.expected = source["expected"];
+	        this.actual = source["actual"];
+	    }
+	}
+	export class SystemConfig {
+	    version: number;
+	    name: string;
+	    loadports: DeviceConfig[];
+	    aligners: DeviceConfig[];
+	    robots: RobotConfig[];
+	    sequences: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SystemConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.name = source["name"];
+	        this.loadports = this.convertValues(source["loadports"], DeviceConfig);
+	        this.aligners = this.convertValues(source["aligners"], DeviceConfig);
+	        this.robots = this.convertValues(source["robots"], RobotConfig);
+	        this.sequences = source["sequences"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WTRLog {
+	    name: string;
+	    time: string;
+	    level: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WTRLog(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.time = source["time"];
+	        this.level = source["level"];
+	        this.message = source["message"];
+	    }
+	}
+	export class WTRStation {
+	    name: string;
+	    code: number;
+	    slots: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WTRStation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.code = source["code"];
+	        this.slots = source["slots"];
+	    }
+	}
+	export class WTRStatus {
+	    name: string;
+	    connected: boolean;
+	    run: boolean;
+	    stop: boolean;
+	    origin: boolean;
+	    overrun: boolean;
+	    alarm: boolean;
+	    errorCode: string;
+	    servo: boolean;
+	    handA: boolean;
+	    handB: boolean;
+	    raw: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WTRStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.connected = source["connected"];
+	        this.run = source["run"];
+	        this.stop = source["stop"];
+	        this.origin = source["origin"];
+	        this.overrun = source["overrun"];
+	        this.alarm = source["alarm"];
+	        this.errorCode = source["errorCode"];
+	        this.servo = source["servo"];
+	        this.handA = source["handA"];
+	        this.handB = source["handB"];
+	        this.raw = source["raw"];
+	    }
+	}
+
+}
+
+export namespace Modbus {
+	
+	export class Config {
+	    mode: string;
+	    connection: any;
+	    timeoutMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.connection = source["connection"];
+	        this.timeoutMs = source["timeoutMs"];
+	    }
+	}
+	export class Request {
+	    unit: number;
+	    table: string;
+	    address: number;
+	    count: number;
+	    bits: boolean[];
+	    registers: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Request(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.unit = source["unit"];
+	        this.table = source["table"];
+	        this.address = source["address"];
+	        this.count = source["count"];
+	        this.bits = source["bits"];
+	        this.registers = source["registers"];
+	    }
+	}
+	export class Result {
+	    bits: boolean[];
+	    registers: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bits = source["bits"];
+	        this.registers = source["registers"];
+	    }
+	}
+
+}
+
+export namespace Simulator {
+	
+	export class SimConfig {
+	    name: string;
+	    kind: string;
+	    address: string;
+	    delayMs: number;
+	    fault: string;
+	    alarmCode: string;
+	    slotMap: string;
+	    password: string;
+	    responses: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new SimConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.address = source["address"];
+	        this.delayMs = source["delayMs"];
+	        this.fault = source["fault"];
+	        this.alarmCode = source["alarmCode"];
+	        this.slotMap = source["slotMap"];
+	        this.password = source["password"];
+	        this.responses = source["responses"];
+	    }
+	}
+
+}
+
+export namespace backend {
+	
+	export class ExpectResult {
+	    command: string;
+	    response: string;
+	    lines: string[];
+	    groups: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ExpectResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.response = source["response"];
+	        this.lines = source["lines"];
+	        this.groups = source["groups"];
+	    }
+	}
+	export class FramingConfig {
+	    mode: string;
+	    delimiter: string;
+	    length: number;
+	    lengthSize: number;
+	    littleEndian: boolean;
+	    idleGapMs: number;
+	    flushTimeoutMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FramingConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.delimiter = source["delimiter"];
+	        this.length = source["length"];
+	        this.lengthSize = source["lengthSize"];
+	        this.littleEndian = source["littleEndian"];
+	        this.idleGapMs = source["idleGapMs"];
+	        this.flushTimeoutMs = source["flushTimeoutMs"];
+	    }
+	}
+	export class HSMSConfig {
+	    mode: string;
+	    sessionId: number;
+	    t3Ms: number;
+	    t5Ms: number;
+	    t6Ms: number;
+	    t7Ms: number;
+	    t8Ms: number;
+	    linktestMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HSMSConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.sessionId = source["sessionId"];
+	        this.t3Ms = source["t3Ms"];
+	        this.t5Ms = source["t5Ms"];
+	        this.t6Ms = source["t6Ms"];
+	        this.t7Ms = source["t7Ms"];
+	        this.t8Ms = source["t8Ms"];
+	        this.linktestMs = source["linktestMs"];
+	    }
+	}
+	export class ModemLines {
+	    dtr: boolean;
+	    rts: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModemLines(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dtr = source["dtr"];
+	        this.rts = source["rts"];
+	    }
+	}
+	export class ReconnectPolicy {
+	    enabled: boolean;
+	    initialDelayMs: number;
+	    maxDelayMs: number;
+	    maxAttempts: number;
+	    queueSends: boolean;
+	    maxQueue: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReconnectPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.initialDelayMs = source["initialDelayMs"];
+	        this.maxDelayMs = source["maxDelayMs"];
+	        this.maxAttempts = source["maxAttempts"];
+	        this.queueSends = source["queueSends"];
+	        this.maxQueue = source["maxQueue"];
+	    }
+	}
+	export class SECS1Config {
+	    line: string;
+	    deviceId: number;
+	    master: boolean;
+	    t1Ms: number;
+	    t2Ms: number;
+	    t3Ms: number;
+	    t4Ms: number;
+	    retryLimit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SECS1Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.deviceId = source["deviceId"];
+	        this.master = source["master"];
+	        this.t1Ms = source["t1Ms"];
+	        this.t2Ms = source["t2Ms"];
+	        this.t3Ms = source["t3Ms"];
+	        this.t4Ms = source["t4Ms"];
+	        this.retryLimit = source["retryLimit"];
+	    }
+	}
+	export class SerialLineConfig {
+	    baudRate: number;
+	    dataBits: number;
+	    parity: string;
+	    stopBits: string;
+	    flowControl: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SerialLineConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.baudRate = source["baudRate"];
+	        this.dataBits = source["dataBits"];
+	        this.parity = source["parity"];
+	        this.stopBits = source["stopBits"];
+	        this.flowControl = source["flowControl"];
+	    }
+	}
+	export class SessionConfig {
+	    type: string;
+	    address: string;
+	    serial: SerialLineConfig;
+	    telnet: TelnetProfile;
+	    udp: UDPConfig;
+	    secs1: SECS1Config;
+	    hsms: HSMSConfig;
+	    txTerminator: string;
+	    framing: FramingConfig;
+	    reconnect: ReconnectPolicy;
+	    binary: boolean;
+	    modemLines?: ModemLines;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.address = source["address"];
+	        this.serial = this.convertValues(source["serial"], SerialLineConfig);
+	        this.telnet = this.convertValues(source["telnet"], TelnetProfile);
+	        this.udp = this.convertValues(source["udp"], UDPConfig);
+	        this.secs1 = this.convertValues(source["secs1"], SECS1Config);
+	        this.hsms = this.convertValues(source["hsms"], HSMSConfig);
+	        this.txTerminator = source["txTerminator"];
+	        this.framing = this.convertValues(source["framing"], FramingConfig);
+	        this.reconnect = this.convertValues(source["reconnect"], ReconnectPolicy);
+	        this.binary = source["binary"];
+	        this.modemLines = this.convertValues(source["modemLines"], ModemLines);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TelnetProfile {
+	    name: string;
+	    userPrompt: string;
+	    username: string;
+	    passwordPrompt: string;
+	    password: string;
+	    readyPrompt: string;
+	    responseEnd: string;
+	    loginTimeoutMs: number;
+	    streaming: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TelnetProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.userPrompt = source["userPrompt"];
+	        this.username = source["username"];
+	        this.passwordPrompt = source["passwordPrompt"];
+	        this.password = source["password"];
+	        this.readyPrompt = source["readyPrompt"];
+	        this.responseEnd = source["responseEnd"];
+	        this.loginTimeoutMs = source["loginTimeoutMs"];
+	        this.streaming = source["streaming"];
+	    }
+	}
+	export class UDPConfig {
+	    localAddress: string;
+	    peerOnly: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new UDPConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.localAddress = source["localAddress"];
+	        this.peerOnly = source["peerOnly"];
+	    }
+	}
+
+}